@@ -0,0 +1,392 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"log"
+	"net/http"
+
+	"github.com/cameronbarnes/go_chirpy/internal/chirptext"
+	"github.com/cameronbarnes/go_chirpy/internal/database"
+	"github.com/google/uuid"
+)
+
+type mentionOut struct {
+	UserID uuid.UUID `json:"user_id"`
+	Handle string    `json:"handle"`
+}
+
+type chirpOut struct {
+	database.Chirp
+	Mentions       []mentionOut    `json:"mentions"`
+	RechirpCount   int64           `json:"rechirp_count"`
+	LikeCount      int64           `json:"like_count"`
+	LikedByMe      bool            `json:"liked_by_me"`
+	Media          []mediaOut      `json:"media"`
+	Poll           *pollOut        `json:"poll,omitempty"`
+	Pinned         bool            `json:"pinned"`
+	Display        string          `json:"display"`
+	RechirpedChirp *database.Chirp `json:"rechirped_chirp,omitempty"`
+	QuotedChirp    *database.Chirp `json:"quoted_chirp,omitempty"`
+}
+
+// chirpRemoved reports whether the chirp was deleted, either recently enough
+// to be restored or for good with only a tombstone left behind.
+func chirpRemoved(chirp database.Chirp) bool {
+	return chirp.IsTombstone || chirp.DeletedAt.Valid
+}
+
+// Who can see a chirp besides its author. Followers-only and mentioned-only
+// chirps are also shown to the users they mention.
+const (
+	visibilityPublic    = "public"
+	visibilityFollowers = "followers"
+	visibilityMentioned = "mentioned"
+	visibilityPrivate   = "private"
+)
+
+// How a user wants chirps with a content warning or the sensitive label shown.
+const (
+	sensitiveHide = "hide"
+	sensitiveBlur = "blur"
+	sensitiveShow = "show"
+)
+
+// How a chirp is returned. Blurred chirps are sent in full for the client to
+// blur, while collapsed ones leave out everything but their labels.
+const (
+	displayFull      = "full"
+	displayBlur      = "blur"
+	displayCollapsed = "collapsed"
+)
+
+const maxContentWarningLength = 100
+
+func validSensitiveContent(pref string) bool {
+	return pref == sensitiveHide || pref == sensitiveBlur || pref == sensitiveShow
+}
+
+// chirpDisplay decides how a chirp is shown to viewer. Anonymous callers get
+// the blur default, and authors always see their own chirps in full.
+func chirpDisplay(chirp database.Chirp, viewer *database.GetUserRow) string {
+	if (chirp.ContentWarning == "" && !chirp.Sensitive) || (viewer != nil && viewer.ID == chirp.UserID) {
+		return displayFull
+	}
+	pref := sensitiveBlur
+	if viewer != nil {
+		pref = viewer.SensitiveContent
+	}
+	switch pref {
+	case sensitiveShow:
+		return displayFull
+	case sensitiveHide:
+		return displayCollapsed
+	}
+	return displayBlur
+}
+
+func validVisibility(visibility string) bool {
+	switch visibility {
+	case visibilityPublic, visibilityFollowers, visibilityMentioned, visibilityPrivate:
+		return true
+	}
+	return false
+}
+
+// chirpPublic reports whether the chirp can be shown to everyone. Scheduled
+// chirps aren't public until they're published, and chirps hidden by
+// moderation stop being public.
+func chirpPublic(chirp database.Chirp) bool {
+	return chirp.Published && !chirp.HiddenAt.Valid && chirp.Visibility == visibilityPublic
+}
+
+// visibleChirps returns the IDs of the chirps viewer may see. Authors can
+// always see their own chirps, so they can tell what's scheduled or appeal a
+// hidden one. Only chirps limited to followers or mentions need the database
+// to decide.
+func (c *apiConfig) visibleChirps(chirps []database.Chirp, viewer *database.GetUserRow) (map[uuid.UUID]bool, error) {
+	out, check := splitVisibleChirps(chirps, viewer)
+	if len(check) == 0 {
+		return out, nil
+	}
+	ids, err := c.db.FilterVisibleChirpIDs(context.Background(), database.FilterVisibleChirpIDsParams{Ids: check, ViewerID: viewer.ID})
+	if err != nil {
+		return nil, err
+	}
+	for _, id := range ids {
+		out[id] = true
+	}
+	return out, nil
+}
+
+// splitVisibleChirps decides what it can without the database. It returns the
+// chirps viewer can see, and the ones that need checking against follows and
+// mentions. It follows the same rule as chirp_visible_to in SQL.
+func splitVisibleChirps(chirps []database.Chirp, viewer *database.GetUserRow) (map[uuid.UUID]bool, []uuid.UUID) {
+	out := map[uuid.UUID]bool{}
+	check := []uuid.UUID{}
+	for _, chirp := range chirps {
+		switch {
+		case viewer != nil && viewer.ID == chirp.UserID:
+			out[chirp.ID] = true
+		case !chirp.Published || chirp.HiddenAt.Valid:
+		case chirp.Visibility == visibilityPublic:
+			out[chirp.ID] = true
+		case viewer != nil && chirp.Visibility != visibilityPrivate:
+			check = append(check, chirp.ID)
+		}
+	}
+	return out, check
+}
+
+// viewerID is the viewer to pass to listing queries, which leave out chirps
+// the viewer can't see. It's null for anonymous callers.
+func viewerID(viewer *database.GetUserRow) uuid.NullUUID {
+	if viewer == nil {
+		return uuid.NullUUID{}
+	}
+	return uuid.NullUUID{UUID: viewer.ID, Valid: true}
+}
+
+func (c *apiConfig) chirpVisibleTo(chirp database.Chirp, viewer *database.GetUserRow) (bool, error) {
+	visible, err := c.visibleChirps([]database.Chirp{chirp}, viewer)
+	return visible[chirp.ID], err
+}
+
+// saveChirpEntities stores the hashtags and mentions parsed out of the chirp
+// body. It should run in the same transaction that wrote the body.
+func saveChirpEntities(q *database.Queries, chirp database.Chirp) error {
+	if err := saveHashtags(q, chirp); err != nil {
+		return err
+	}
+	return saveMentions(q, chirp)
+}
+
+// chirpEntities parses the URLs, mentions and hashtags in a chirp body into
+// the JSON stored with the chirp, so clients don't each parse it their own
+// way. Entities hold only strings and ints, so marshalling can't fail.
+func chirpEntities(body string) json.RawMessage {
+	out, _ := json.Marshal(chirptext.Entities(body))
+	return out
+}
+
+// unparsedEntities reports whether the chirp was written before entities were
+// stored. backfillEntities fills these in.
+func unparsedEntities(chirp database.Chirp) bool {
+	return string(chirp.Entities) == "null"
+}
+
+// backfillEntities parses entities for chirps written before they were
+// stored, a batch at a time, and returns once none are left.
+func (c *apiConfig) backfillEntities() {
+	for {
+		chirps, err := c.db.ListUnparsedChirps(context.Background())
+		if err != nil {
+			log.Printf("Failed to list chirps to parse with err: %s", err)
+			return
+		}
+		if len(chirps) == 0 {
+			return
+		}
+		for _, chirp := range chirps {
+			err := c.db.SetChirpEntities(context.Background(), database.SetChirpEntitiesParams{ID: chirp.ID, Entities: chirpEntities(chirp.Body)})
+			if err != nil {
+				log.Printf("Failed to save chirp entities with err: %s", err)
+				return
+			}
+		}
+	}
+}
+
+func clearChirpEntities(q *database.Queries, id uuid.UUID) error {
+	if err := q.DeleteHashtagsForChirp(context.Background(), id); err != nil {
+		return err
+	}
+	return q.DeleteMentionsForChirp(context.Background(), id)
+}
+
+// buildChirps attaches the data stored alongside each chirp, loading it for
+// the whole slice at once rather than one query per chirp. viewer is nil for
+// anonymous callers.
+func (c *apiConfig) buildChirps(chirps []database.Chirp, viewer *database.GetUserRow) ([]chirpOut, error) {
+	ids := make([]uuid.UUID, len(chirps))
+	for i, chirp := range chirps {
+		ids[i] = chirp.ID
+	}
+	mentions, err := c.db.GetMentionsForChirps(context.Background(), ids)
+	if err != nil {
+		return nil, err
+	}
+	mentionsByChirp := map[uuid.UUID][]mentionOut{}
+	for _, mention := range mentions {
+		handle := ""
+		if mention.Handle != nil {
+			handle = *mention.Handle
+		}
+		mentionsByChirp[mention.ChirpID] = append(mentionsByChirp[mention.ChirpID], mentionOut{UserID: mention.UserID, Handle: handle})
+	}
+	rechirpCounts, err := c.db.CountRechirpsForChirps(context.Background(), ids)
+	if err != nil {
+		return nil, err
+	}
+	rechirpsByChirp := map[uuid.UUID]int64{}
+	for _, count := range rechirpCounts {
+		rechirpsByChirp[count.RechirpOf.UUID] = count.RechirpCount
+	}
+	likeCounts, err := c.db.CountLikesForChirps(context.Background(), ids)
+	if err != nil {
+		return nil, err
+	}
+	likesByChirp := map[uuid.UUID]int64{}
+	for _, count := range likeCounts {
+		likesByChirp[count.ChirpID] = count.LikeCount
+	}
+	attached, err := c.db.GetMediaForChirps(context.Background(), ids)
+	if err != nil {
+		return nil, err
+	}
+	mediaByChirp := map[uuid.UUID][]mediaOut{}
+	for _, item := range attached {
+		mediaByChirp[item.ChirpID.UUID] = append(mediaByChirp[item.ChirpID.UUID], mediaOutFrom(item))
+	}
+	polls, err := c.loadPolls(ids, viewer)
+	if err != nil {
+		return nil, err
+	}
+	likedByViewer := map[uuid.UUID]bool{}
+	if viewer != nil {
+		liked, err := c.db.GetLikedChirpIDs(context.Background(), database.GetLikedChirpIDsParams{UserID: viewer.ID, ChirpIds: ids})
+		if err != nil {
+			return nil, err
+		}
+		for _, id := range liked {
+			likedByViewer[id] = true
+		}
+	}
+	referenced, err := c.referencedChirps(chirps)
+	if err != nil {
+		return nil, err
+	}
+	out := make([]chirpOut, 0, len(chirps))
+	for _, chirp := range chirps {
+		if unparsedEntities(chirp) {
+			chirp.Entities = chirpEntities(chirp.Body)
+		}
+		original, found := referenced[chirp.RechirpOf.UUID]
+		if chirp.RechirpOf.Valid && !found {
+			// The rechirped chirp has been deleted, so there's nothing to show.
+			continue
+		}
+		item := chirpOut{
+			Chirp:        chirp,
+			Mentions:     mentionsByChirp[chirp.ID],
+			RechirpCount: rechirpsByChirp[chirp.ID],
+			LikeCount:    likesByChirp[chirp.ID],
+			LikedByMe:    likedByViewer[chirp.ID],
+			Media:        mediaByChirp[chirp.ID],
+			Poll:         polls[chirp.ID],
+		}
+		if item.Mentions == nil {
+			item.Mentions = []mentionOut{}
+		}
+		if item.Media == nil {
+			item.Media = []mediaOut{}
+		}
+		if chirp.RechirpOf.Valid {
+			item.RechirpedChirp = &original
+		}
+		if quoted, ok := referenced[chirp.QuoteOf.UUID]; ok && chirp.QuoteOf.Valid {
+			item.QuotedChirp = &quoted
+		}
+		item = applyDisplay(item, viewer)
+		out = append(out, item)
+	}
+	return out, nil
+}
+
+// applyDisplay works out how item is shown to viewer, and strips everything
+// but the labels from what's collapsed. A rechirp carries the labels of the
+// chirp it points at, while a quoted chirp is collapsed on its own labels.
+func applyDisplay(item chirpOut, viewer *database.GetUserRow) chirpOut {
+	item.Display = chirpDisplay(item.Chirp, viewer)
+	if item.RechirpedChirp != nil {
+		item.Display = chirpDisplay(*item.RechirpedChirp, viewer)
+		if item.Display == displayCollapsed {
+			item.RechirpedChirp = collapsedChirp(*item.RechirpedChirp)
+		}
+	}
+	if item.QuotedChirp != nil && chirpDisplay(*item.QuotedChirp, viewer) == displayCollapsed {
+		item.QuotedChirp = collapsedChirp(*item.QuotedChirp)
+	}
+	if item.Display == displayCollapsed {
+		item.Body = ""
+		item.Entities = json.RawMessage("[]")
+		item.Mentions = []mentionOut{}
+		item.Media = []mediaOut{}
+		item.Poll = nil
+		item.QuotedChirp = nil
+	}
+	return item
+}
+
+func collapsedChirp(chirp database.Chirp) *database.Chirp {
+	chirp.Body = ""
+	chirp.Entities = json.RawMessage("[]")
+	return &chirp
+}
+
+// referencedChirps loads the chirps that rechirps and quotes point at, keyed
+// by ID. Only public chirps can be rechirped or quoted, so anything that
+// isn't public any more is left out.
+func (c *apiConfig) referencedChirps(chirps []database.Chirp) (map[uuid.UUID]database.Chirp, error) {
+	ids := []uuid.UUID{}
+	for _, chirp := range chirps {
+		if chirp.RechirpOf.Valid {
+			ids = append(ids, chirp.RechirpOf.UUID)
+		}
+		if chirp.QuoteOf.Valid {
+			ids = append(ids, chirp.QuoteOf.UUID)
+		}
+	}
+	out := map[uuid.UUID]database.Chirp{}
+	if len(ids) == 0 {
+		return out, nil
+	}
+	found, err := c.db.GetChirpsByIDs(context.Background(), ids)
+	if err != nil {
+		return nil, err
+	}
+	for _, chirp := range found {
+		if !chirpRemoved(chirp) && chirpPublic(chirp) {
+			if unparsedEntities(chirp) {
+				chirp.Entities = chirpEntities(chirp.Body)
+			}
+			out[chirp.ID] = chirp
+		}
+	}
+	return out, nil
+}
+
+func (c *apiConfig) respondWithChirps(w http.ResponseWriter, code int, chirps []database.Chirp, viewer *database.GetUserRow) {
+	out, err := c.buildChirps(chirps, viewer)
+	if err != nil {
+		log.Printf("Failed to build chirps with err: %s", err)
+		respondWithError(w, 500, "Failed to get chirps")
+		return
+	}
+	respondWithJSON(w, code, out)
+}
+
+func (c *apiConfig) respondWithChirp(w http.ResponseWriter, code int, chirp database.Chirp, viewer *database.GetUserRow) {
+	out, err := c.buildChirps([]database.Chirp{chirp}, viewer)
+	if err != nil {
+		log.Printf("Failed to build chirp with err: %s", err)
+		respondWithError(w, 500, "Failed to get Chirp")
+		return
+	}
+	if len(out) == 0 {
+		respondWithError(w, 410, "Chirp has been deleted")
+		return
+	}
+	respondWithJSON(w, code, out[0])
+}
@@ -0,0 +1,166 @@
+package main
+
+import (
+	"database/sql"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/cameronbarnes/go_chirpy/internal/database"
+	"github.com/google/uuid"
+)
+
+func TestChirpDisplay(t *testing.T) {
+	author := uuid.New()
+	viewerWith := func(pref string) *database.GetUserRow {
+		return &database.GetUserRow{ID: uuid.New(), SensitiveContent: pref}
+	}
+	plain := database.Chirp{UserID: author, Body: "hi"}
+	warned := database.Chirp{UserID: author, Body: "hi", ContentWarning: "spoilers"}
+	sensitive := database.Chirp{UserID: author, Body: "hi", Sensitive: true}
+	tests := []struct {
+		name   string
+		chirp  database.Chirp
+		viewer *database.GetUserRow
+		want   string
+	}{
+		{"unlabelled anonymous", plain, nil, displayFull},
+		{"unlabelled hide", plain, viewerWith(sensitiveHide), displayFull},
+		{"warning anonymous", warned, nil, displayBlur},
+		{"warning show", warned, viewerWith(sensitiveShow), displayFull},
+		{"warning blur", warned, viewerWith(sensitiveBlur), displayBlur},
+		{"warning hide", warned, viewerWith(sensitiveHide), displayCollapsed},
+		{"sensitive anonymous", sensitive, nil, displayBlur},
+		{"sensitive show", sensitive, viewerWith(sensitiveShow), displayFull},
+		{"sensitive blur", sensitive, viewerWith(sensitiveBlur), displayBlur},
+		{"sensitive hide", sensitive, viewerWith(sensitiveHide), displayCollapsed},
+		{"author who hides", sensitive, &database.GetUserRow{ID: author, SensitiveContent: sensitiveHide}, displayFull},
+	}
+	for _, test := range tests {
+		if got := chirpDisplay(test.chirp, test.viewer); got != test.want {
+			t.Errorf("%s: expected %s, got %s", test.name, test.want, got)
+		}
+	}
+}
+
+func TestApplyDisplayRechirpInheritsLabels(t *testing.T) {
+	viewer := &database.GetUserRow{ID: uuid.New(), SensitiveContent: sensitiveHide}
+	original := database.Chirp{ID: uuid.New(), UserID: uuid.New(), Body: "gory", Sensitive: true}
+	rechirp := chirpOut{
+		Chirp:          database.Chirp{ID: uuid.New(), UserID: uuid.New(), RechirpOf: uuid.NullUUID{UUID: original.ID, Valid: true}},
+		RechirpedChirp: &original,
+	}
+	got := applyDisplay(rechirp, viewer)
+	if got.Display != displayCollapsed {
+		t.Errorf("Rechirp should take the original's labels, got %s", got.Display)
+	}
+	if got.RechirpedChirp.Body != "" || string(got.RechirpedChirp.Entities) != "[]" {
+		t.Errorf("Collapsed rechirp leaked the original: %+v", got.RechirpedChirp)
+	}
+	if original.Body != "gory" {
+		t.Error("Collapsing shouldn't change the chirp it was given")
+	}
+	if got := applyDisplay(rechirp, nil); got.Display != displayBlur || got.RechirpedChirp.Body != "gory" {
+		t.Errorf("Anonymous viewers should get the original to blur, got %s %+v", got.Display, got.RechirpedChirp)
+	}
+}
+
+func TestApplyDisplayCollapsesQuotesOnTheirOwnLabels(t *testing.T) {
+	viewer := &database.GetUserRow{ID: uuid.New(), SensitiveContent: sensitiveHide}
+	quoted := database.Chirp{ID: uuid.New(), UserID: uuid.New(), Body: "spoiler", ContentWarning: "film"}
+	item := chirpOut{Chirp: database.Chirp{ID: uuid.New(), UserID: uuid.New(), Body: "look"}, QuotedChirp: &quoted}
+	got := applyDisplay(item, viewer)
+	if got.Display != displayFull || got.Body != "look" {
+		t.Errorf("The quoting chirp has no labels of its own, got %s %q", got.Display, got.Body)
+	}
+	if got.QuotedChirp == nil || got.QuotedChirp.Body != "" {
+		t.Errorf("Quoted chirp should be collapsed, got %+v", got.QuotedChirp)
+	}
+}
+
+func TestApplyDisplayCollapsedStripsAttachments(t *testing.T) {
+	viewer := &database.GetUserRow{ID: uuid.New(), SensitiveContent: sensitiveHide}
+	item := chirpOut{
+		Chirp:    database.Chirp{ID: uuid.New(), UserID: uuid.New(), Body: "nsfw", Sensitive: true},
+		Mentions: []mentionOut{{UserID: uuid.New()}},
+		Media:    []mediaOut{{ID: uuid.New()}},
+		Poll:     &pollOut{},
+	}
+	got := applyDisplay(item, viewer)
+	if got.Body != "" || len(got.Mentions) != 0 || len(got.Media) != 0 || got.Poll != nil {
+		t.Errorf("Collapsed chirp kept content: %+v", got)
+	}
+}
+
+func TestSplitVisibleChirps(t *testing.T) {
+	author := &database.GetUserRow{ID: uuid.New()}
+	other := &database.GetUserRow{ID: uuid.New()}
+	chirpWith := func(visibility string, published, hidden bool) database.Chirp {
+		return database.Chirp{
+			ID:         uuid.New(),
+			UserID:     author.ID,
+			Published:  published,
+			HiddenAt:   sql.NullTime{Time: time.Now(), Valid: hidden},
+			Visibility: visibility,
+		}
+	}
+	tests := []struct {
+		name      string
+		chirp     database.Chirp
+		viewer    *database.GetUserRow
+		wantShown bool
+		wantCheck bool
+	}{
+		{"public anonymous", chirpWith(visibilityPublic, true, false), nil, true, false},
+		{"public other", chirpWith(visibilityPublic, true, false), other, true, false},
+		{"author private", chirpWith(visibilityPrivate, true, false), author, true, false},
+		{"author unpublished", chirpWith(visibilityPublic, false, false), author, true, false},
+		{"author hidden", chirpWith(visibilityFollowers, true, true), author, true, false},
+		{"unpublished other", chirpWith(visibilityPublic, false, false), other, false, false},
+		{"hidden other", chirpWith(visibilityPublic, true, true), other, false, false},
+		{"hidden followers other", chirpWith(visibilityFollowers, true, true), other, false, false},
+		{"private other", chirpWith(visibilityPrivate, true, false), other, false, false},
+		{"private anonymous", chirpWith(visibilityPrivate, true, false), nil, false, false},
+		{"followers anonymous", chirpWith(visibilityFollowers, true, false), nil, false, false},
+		{"mentioned anonymous", chirpWith(visibilityMentioned, true, false), nil, false, false},
+		{"followers other", chirpWith(visibilityFollowers, true, false), other, false, true},
+		{"mentioned other", chirpWith(visibilityMentioned, true, false), other, false, true},
+	}
+	for _, test := range tests {
+		shown, check := splitVisibleChirps([]database.Chirp{test.chirp}, test.viewer)
+		if shown[test.chirp.ID] != test.wantShown {
+			t.Errorf("%s: expected shown %v, got %v", test.name, test.wantShown, shown[test.chirp.ID])
+		}
+		if checked := slices.Contains(check, test.chirp.ID); checked != test.wantCheck {
+			t.Errorf("%s: expected check %v, got %v", test.name, test.wantCheck, checked)
+		}
+	}
+}
+
+func TestVisibleChirpsAnonymousSkipsDatabase(t *testing.T) {
+	// No database is set up, so this fails if any chirp needs a lookup.
+	c := &apiConfig{}
+	public := database.Chirp{ID: uuid.New(), Published: true, Visibility: visibilityPublic}
+	followers := database.Chirp{ID: uuid.New(), Published: true, Visibility: visibilityFollowers}
+	mentioned := database.Chirp{ID: uuid.New(), Published: true, Visibility: visibilityMentioned}
+	visible, err := c.visibleChirps([]database.Chirp{public, followers, mentioned}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !visible[public.ID] || visible[followers.ID] || visible[mentioned.ID] {
+		t.Errorf("Unexpected visibility: %v", visible)
+	}
+}
+
+func TestValidVisibility(t *testing.T) {
+	for _, visibility := range []string{visibilityPublic, visibilityFollowers, visibilityMentioned, visibilityPrivate} {
+		if !validVisibility(visibility) {
+			t.Errorf("Expected %q to be valid", visibility)
+		}
+	}
+	for _, visibility := range []string{"", "Public", "friends", " public"} {
+		if validVisibility(visibility) {
+			t.Errorf("Expected %q to be invalid", visibility)
+		}
+	}
+}
@@ -0,0 +1,151 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"log"
+	"net/http"
+
+	"github.com/cameronbarnes/go_chirpy/internal/database"
+	"github.com/google/uuid"
+)
+
+// Drafts aren't validated as chirps until they are published, but they still
+// shouldn't be usable as free storage.
+const maxDraftLength = 5000
+
+type draftArgs struct {
+	Body string `json:"body"`
+}
+
+func parseDraftID(w http.ResponseWriter, r *http.Request) (uuid.UUID, bool) {
+	id, err := uuid.Parse(r.PathValue("id"))
+	if err != nil {
+		respondWithError(w, 400, "UUID provided is not valid")
+		return id, false
+	}
+	return id, true
+}
+
+func respondWithDraftError(w http.ResponseWriter, err error) {
+	if errors.Is(err, sql.ErrNoRows) {
+		respondWithError(w, 404, "Draft Not Found")
+		return
+	}
+	log.Printf("Failed to access draft with err: %s", err)
+	respondWithError(w, 500, "Failed to access draft")
+}
+
+func (c *apiConfig) addDraft(w http.ResponseWriter, r *http.Request, user database.GetUserRow) {
+	arg, err := handleParse[draftArgs](w, r)
+	if err != nil {
+		return
+	}
+	if len(arg.Body) > maxDraftLength {
+		respondWithError(w, 400, "Draft is too long")
+		return
+	}
+	draft, err := c.db.CreateDraft(context.Background(), database.CreateDraftParams{UserID: user.ID, Body: arg.Body})
+	if err != nil {
+		respondWithDraftError(w, err)
+		return
+	}
+	respondWithJSON(w, 201, draft)
+}
+
+func (c *apiConfig) getDrafts(w http.ResponseWriter, r *http.Request, user database.GetUserRow) {
+	drafts, err := c.db.ListDrafts(context.Background(), user.ID)
+	if err != nil {
+		respondWithDraftError(w, err)
+		return
+	}
+	if drafts == nil {
+		drafts = []database.Draft{}
+	}
+	respondWithJSON(w, 200, drafts)
+}
+
+func (c *apiConfig) getDraft(w http.ResponseWriter, r *http.Request, user database.GetUserRow) {
+	id, ok := parseDraftID(w, r)
+	if !ok {
+		return
+	}
+	draft, err := c.db.GetDraft(context.Background(), database.GetDraftParams{ID: id, UserID: user.ID})
+	if err != nil {
+		respondWithDraftError(w, err)
+		return
+	}
+	respondWithJSON(w, 200, draft)
+}
+
+func (c *apiConfig) updateDraft(w http.ResponseWriter, r *http.Request, user database.GetUserRow) {
+	id, ok := parseDraftID(w, r)
+	if !ok {
+		return
+	}
+	arg, err := handleParse[draftArgs](w, r)
+	if err != nil {
+		return
+	}
+	if len(arg.Body) > maxDraftLength {
+		respondWithError(w, 400, "Draft is too long")
+		return
+	}
+	draft, err := c.db.UpdateDraft(context.Background(), database.UpdateDraftParams{ID: id, UserID: user.ID, Body: arg.Body})
+	if err != nil {
+		respondWithDraftError(w, err)
+		return
+	}
+	respondWithJSON(w, 200, draft)
+}
+
+func (c *apiConfig) deleteDraft(w http.ResponseWriter, r *http.Request, user database.GetUserRow) {
+	id, ok := parseDraftID(w, r)
+	if !ok {
+		return
+	}
+	_, err := c.db.DeleteDraft(context.Background(), database.DeleteDraftParams{ID: id, UserID: user.ID})
+	if err != nil {
+		respondWithDraftError(w, err)
+		return
+	}
+	w.WriteHeader(204)
+}
+
+// publishDraft turns a draft into a chirp. Removing the draft and adding the
+// chirp share a transaction so a retried request can't publish it twice.
+func (c *apiConfig) publishDraft(w http.ResponseWriter, r *http.Request, user database.GetUserRow) {
+	id, ok := parseDraftID(w, r)
+	if !ok {
+		return
+	}
+	var chirp database.Chirp
+	var invalid error
+	err := c.withTx(func(q *database.Queries) error {
+		draft, err := q.DeleteDraft(context.Background(), database.DeleteDraftParams{ID: id, UserID: user.ID})
+		if err != nil {
+			return err
+		}
+		pending := pendingChirp{Author: user, Body: draft.Body}
+		if err := c.processors.Run(context.Background(), &pending); err != nil {
+			invalid = err
+			return err
+		}
+		chirp, err = q.AddChirp(context.Background(), database.AddChirpParams{Body: pending.Body, UserID: user.ID, Flagged: pending.Flagged, Visibility: visibilityPublic, Entities: chirpEntities(pending.Body)})
+		if err != nil {
+			return err
+		}
+		return saveChirpEntities(q, chirp)
+	})
+	if invalid != nil {
+		respondWithChirpError(w, invalid)
+		return
+	}
+	if err != nil {
+		respondWithDraftError(w, err)
+		return
+	}
+	c.recordTrends(chirp)
+	c.respondWithChirp(w, 201, chirp, &user)
+}
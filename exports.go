@@ -0,0 +1,387 @@
+package main
+
+import (
+	"archive/zip"
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"html/template"
+	"io"
+	"log"
+	"net/http"
+	"os"
+	"time"
+
+	"github.com/cameronbarnes/go_chirpy/internal/auth"
+	"github.com/cameronbarnes/go_chirpy/internal/database"
+	"github.com/cameronbarnes/go_chirpy/internal/storage"
+	"github.com/google/uuid"
+)
+
+// exportDone is the status of a job whose archive is ready. Jobs start out
+// pending, end up done or failed, and done jobs expire once their archive is
+// deleted.
+const exportDone = "done"
+
+const (
+	// exportHeartbeat is how often a running export shows it's still alive,
+	// and exportStaleAfter how long without one before it's given up on.
+	exportHeartbeat  = 30 * time.Second
+	exportStaleAfter = 5 * time.Minute
+)
+
+var mediaExtensions = map[string]string{
+	"image/png":  ".png",
+	"image/jpeg": ".jpg",
+	"image/gif":  ".gif",
+	"image/webp": ".webp",
+}
+
+type exportJobOut struct {
+	ID          uuid.UUID  `json:"id"`
+	CreatedAt   time.Time  `json:"created_at"`
+	Status      string     `json:"status"`
+	Error       string     `json:"error,omitempty"`
+	CompletedAt *time.Time `json:"completed_at"`
+	DownloadURL string     `json:"download_url,omitempty"`
+	ExpiresAt   *time.Time `json:"expires_at,omitempty"`
+}
+
+// exportMedium is a media row along with where its file sits in the archive.
+type exportMedium struct {
+	database.Medium
+	File string `json:"file"`
+}
+
+func exportDownloadPath(id uuid.UUID) string {
+	return "/api/exports/" + id.String() + "/download"
+}
+
+// exportJobOutFrom includes a signed download link once the archive is ready.
+// Links all expire exportURLTTL after the archive was built, which is when
+// purgeExpiredExports deletes it.
+func (c *apiConfig) exportJobOutFrom(job database.ExportJob) exportJobOut {
+	out := exportJobOut{ID: job.ID, CreatedAt: job.CreatedAt, Status: job.Status, Error: job.Error}
+	if job.CompletedAt.Valid {
+		out.CompletedAt = &job.CompletedAt.Time
+	}
+	expiresAt := job.CompletedAt.Time.Add(c.exportURLTTL)
+	if job.Status == exportDone && time.Now().Before(expiresAt) {
+		out.DownloadURL = auth.SignURL(exportDownloadPath(job.ID), c.exportSecret, expiresAt)
+		out.ExpiresAt = &expiresAt
+	}
+	return out
+}
+
+// addExport starts building an archive of everything the caller has stored
+// with us. Only one export can be running per user.
+func (c *apiConfig) addExport(w http.ResponseWriter, _ *http.Request, user database.GetUserRow) {
+	job, err := c.db.CreateExportJob(context.Background(), user.ID)
+	if isUniqueViolation(err) {
+		respondWithError(w, 409, "An export is already in progress")
+		return
+	}
+	if err != nil {
+		log.Printf("Failed to create export job with err: %s", err)
+		respondWithError(w, 500, "Failed to start export")
+		return
+	}
+	go c.runExport(job)
+	respondWithJSON(w, 202, c.exportJobOutFrom(job))
+}
+
+func (c *apiConfig) getExport(w http.ResponseWriter, r *http.Request, user database.GetUserRow) {
+	id, err := uuid.Parse(r.PathValue("id"))
+	if err != nil {
+		respondWithError(w, 400, "UUID provided is not valid")
+		return
+	}
+	job, err := c.db.GetExportJob(context.Background(), id)
+	if err != nil || job.UserID != user.ID {
+		respondWithError(w, 404, "Export Not Found")
+		return
+	}
+	respondWithJSON(w, 200, c.exportJobOutFrom(job))
+}
+
+// downloadExport serves a finished archive to anyone holding a valid signed
+// link, so it can be opened straight from a browser or email.
+func (c *apiConfig) downloadExport(w http.ResponseWriter, r *http.Request) {
+	id, err := uuid.Parse(r.PathValue("id"))
+	if err != nil {
+		respondWithError(w, 400, "UUID provided is not valid")
+		return
+	}
+	err = auth.CheckSignedURL(exportDownloadPath(id), r.URL.Query(), c.exportSecret)
+	if errors.Is(err, auth.ErrSignatureExpired) {
+		respondWithError(w, 410, "Download link has expired")
+		return
+	}
+	if err != nil {
+		respondWithError(w, 403, "Download link is not valid")
+		return
+	}
+	job, err := c.db.GetExportJob(context.Background(), id)
+	if err != nil || job.Status != exportDone {
+		respondWithError(w, 404, "Export Not Found")
+		return
+	}
+	file, err := c.exports.Open(context.Background(), job.ID.String())
+	if errors.Is(err, storage.ErrNotFound) {
+		respondWithError(w, 404, "Export Not Found")
+		return
+	}
+	if err != nil {
+		log.Printf("Failed to open export with err: %s", err)
+		respondWithError(w, 500, "Failed to get export")
+		return
+	}
+	defer file.Close()
+	w.Header().Set("Content-Type", "application/zip")
+	w.Header().Set("Content-Disposition", `attachment; filename="chirpy-export-`+job.CreatedAt.Format("2006-01-02")+`.zip"`)
+	w.Header().Set("Cache-Control", "private, no-store")
+	w.WriteHeader(200)
+	io.Copy(w, file)
+}
+
+func (c *apiConfig) runExport(job database.ExportJob) {
+	done := make(chan struct{})
+	defer close(done)
+	go c.keepExportAlive(job.ID, done)
+	if err := c.buildExport(job); err != nil {
+		log.Printf("Failed to build export with err: %s", err)
+		err := c.db.FailExportJob(context.Background(), database.FailExportJobParams{ID: job.ID, Error: "Failed to build export"})
+		if err != nil {
+			log.Printf("Failed to mark export failed with err: %s", err)
+		}
+		return
+	}
+	_, err := c.db.CompleteExportJob(context.Background(), job.ID)
+	if errors.Is(err, sql.ErrNoRows) {
+		// The job was given up on as stale while we were building it, so
+		// nothing will ever link to the archive.
+		if err := c.exports.Delete(context.Background(), job.ID.String()); err != nil {
+			log.Printf("Failed to delete abandoned export with err: %s", err)
+		}
+		return
+	}
+	if err != nil {
+		log.Printf("Failed to complete export with err: %s", err)
+	}
+}
+
+// keepExportAlive keeps the job marked alive until done is closed, so other
+// instances don't fail it as stale.
+func (c *apiConfig) keepExportAlive(id uuid.UUID, done <-chan struct{}) {
+	ticker := time.NewTicker(exportHeartbeat)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-done:
+			return
+		case <-ticker.C:
+			if err := c.db.TouchExportJob(context.Background(), id); err != nil {
+				log.Printf("Failed to touch export job with err: %s", err)
+			}
+		}
+	}
+}
+
+// cleanUpExports runs until the process exits. It fails exports whose
+// instance stopped sending heartbeats, and deletes archives whose links have
+// expired. Both are safe to run on several instances at once.
+func (c *apiConfig) cleanUpExports(interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		if err := c.db.FailStaleExportJobs(context.Background(), time.Now().UTC().Add(-exportStaleAfter)); err != nil {
+			log.Printf("Failed to fail stale exports with err: %s", err)
+		}
+		if err := c.purgeExpiredExports(); err != nil {
+			log.Printf("Failed to purge expired exports with err: %s", err)
+		}
+		<-ticker.C
+	}
+}
+
+func (c *apiConfig) purgeExpiredExports() error {
+	cutoff := sql.NullTime{Time: time.Now().UTC().Add(-c.exportURLTTL), Valid: true}
+	ids, err := c.db.ListExpiredExportJobs(context.Background(), cutoff)
+	if err != nil {
+		return err
+	}
+	for _, id := range ids {
+		// Delete the archive first, so a failure leaves the job to retry.
+		if err := c.exports.Delete(context.Background(), id.String()); err != nil {
+			return err
+		}
+		if err := c.db.ExpireExportJob(context.Background(), id); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// buildExport writes the archive to a temporary file first, since media can
+// make it too big to hold in memory, then hands it to the export store.
+func (c *apiConfig) buildExport(job database.ExportJob) error {
+	tmp, err := os.CreateTemp("", "chirpy-export-*.zip")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+	defer tmp.Close()
+	if err := c.writeExport(tmp, job.UserID); err != nil {
+		return err
+	}
+	if _, err := tmp.Seek(0, io.SeekStart); err != nil {
+		return err
+	}
+	return c.exports.Put(context.Background(), job.ID.String(), tmp)
+}
+
+func (c *apiConfig) writeExport(out io.Writer, userID uuid.UUID) error {
+	ctx := context.Background()
+	profile, err := c.db.GetUser(ctx, userID)
+	if err != nil {
+		return err
+	}
+	chirps, err := c.db.ListChirpsForExport(ctx, userID)
+	if err != nil {
+		return err
+	}
+	likes, err := c.db.ListLikesForExport(ctx, userID)
+	if err != nil {
+		return err
+	}
+	items, err := c.db.ListMediaForExport(ctx, userID)
+	if err != nil {
+		return err
+	}
+	sessions, err := c.db.ListSessionsForExport(ctx, userID)
+	if err != nil {
+		return err
+	}
+	media := make([]exportMedium, len(items))
+	for i, item := range items {
+		media[i] = exportMedium{Medium: item, File: "media/" + item.ID.String() + mediaExtensions[item.MimeType]}
+	}
+
+	archive := zip.NewWriter(out)
+	files := []struct {
+		Name string
+		Data any
+	}{
+		{Name: "profile.json", Data: profile},
+		{Name: "chirps.json", Data: chirps},
+		{Name: "likes.json", Data: likes},
+		{Name: "media.json", Data: media},
+		{Name: "sessions.json", Data: sessions},
+	}
+	for _, file := range files {
+		if err := writeExportJSON(archive, file.Name, file.Data); err != nil {
+			return err
+		}
+	}
+	for _, item := range media {
+		if err := c.copyExportMedia(archive, item); err != nil {
+			return err
+		}
+	}
+	viewer, err := archive.Create("index.html")
+	if err != nil {
+		return err
+	}
+	if err := exportViewer.Execute(viewer, newExportView(profile, chirps, media)); err != nil {
+		return err
+	}
+	return archive.Close()
+}
+
+func writeExportJSON(archive *zip.Writer, name string, data any) error {
+	file, err := archive.Create(name)
+	if err != nil {
+		return err
+	}
+	enc := json.NewEncoder(file)
+	enc.SetIndent("", "  ")
+	return enc.Encode(data)
+}
+
+func (c *apiConfig) copyExportMedia(archive *zip.Writer, item exportMedium) error {
+	src, err := c.media.Open(context.Background(), item.Sha256)
+	if errors.Is(err, storage.ErrNotFound) {
+		// Keep the rest of the archive if a file has gone missing; media.json
+		// still records what the upload was.
+		log.Printf("Media %s missing from storage during export", item.ID)
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	defer src.Close()
+	// Images are already compressed, so store them as is.
+	dest, err := archive.CreateHeader(&zip.FileHeader{Name: item.File, Method: zip.Store, Modified: item.CreatedAt})
+	if err != nil {
+		return err
+	}
+	_, err = io.Copy(dest, src)
+	return err
+}
+
+type exportView struct {
+	Profile database.GetUserRow
+	Chirps  []exportChirpView
+}
+
+type exportChirpView struct {
+	database.Chirp
+	Media []exportMedium
+}
+
+func newExportView(profile database.GetUserRow, chirps []database.Chirp, media []exportMedium) exportView {
+	byChirp := map[uuid.UUID][]exportMedium{}
+	for _, item := range media {
+		if item.ChirpID.Valid {
+			byChirp[item.ChirpID.UUID] = append(byChirp[item.ChirpID.UUID], item)
+		}
+	}
+	view := exportView{Profile: profile, Chirps: make([]exportChirpView, len(chirps))}
+	for i, chirp := range chirps {
+		view.Chirps[i] = exportChirpView{Chirp: chirp, Media: byChirp[chirp.ID]}
+	}
+	return view
+}
+
+// exportViewer is a single page that lets people browse their archive
+// offline without having to read the JSON.
+var exportViewer = template.Must(template.New("index.html").Parse(`<!DOCTYPE html>
+<html>
+<head>
+  <meta charset="utf-8">
+  <title>Chirpy archive</title>
+  <style>
+    body { font-family: sans-serif; max-width: 40em; margin: 2em auto; }
+    article { border-bottom: 1px solid #ddd; padding: 1em 0; }
+    time, .note { color: #666; font-size: 0.9em; }
+    img { max-width: 100%; }
+  </style>
+</head>
+<body>
+  <h1>{{with .Profile.Handle}}@{{.}}{{else}}{{.Profile.Email}}{{end}}</h1>
+  <p class="note">{{.Profile.Email}} &middot; joined {{.Profile.CreatedAt.Format "2 Jan 2006"}}</p>
+  <p class="note">Likes, media details and sessions are in the JSON files next to this page.</p>
+  {{range .Chirps}}
+  <article>
+    <time>{{.CreatedAt.Format "2 Jan 2006 15:04"}}</time>
+    {{if .DeletedAt.Valid}}<span class="note">(deleted)</span>{{end}}
+    {{if .RechirpOf.Valid}}<p class="note">Rechirp of {{.RechirpOf.UUID}}</p>{{else}}<p>{{.Body}}</p>{{end}}
+    {{range .Media}}<img src="{{.File}}" alt="{{.AltText}}">{{end}}
+  </article>
+  {{else}}
+  <p>No Chirps yet.</p>
+  {{end}}
+</body>
+</html>
+`))
@@ -0,0 +1,54 @@
+package main
+
+import (
+	"database/sql"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/cameronbarnes/go_chirpy/internal/auth"
+	"github.com/cameronbarnes/go_chirpy/internal/database"
+	"github.com/google/uuid"
+)
+
+func TestExportJobOutFromExpiry(t *testing.T) {
+	c := &apiConfig{exportSecret: "secret", exportURLTTL: time.Hour}
+	completed := func(ago time.Duration) sql.NullTime {
+		return sql.NullTime{Time: time.Now().Add(-ago), Valid: true}
+	}
+	tests := []struct {
+		name     string
+		job      database.ExportJob
+		wantLink bool
+	}{
+		{name: "pending", job: database.ExportJob{Status: "pending"}},
+		{name: "failed", job: database.ExportJob{Status: "failed", Error: "boom", CompletedAt: completed(time.Minute)}},
+		{name: "done within the TTL", job: database.ExportJob{Status: exportDone, CompletedAt: completed(time.Minute)}, wantLink: true},
+		{name: "done past the TTL", job: database.ExportJob{Status: exportDone, CompletedAt: completed(2 * time.Hour)}},
+		{name: "expired", job: database.ExportJob{Status: "expired", CompletedAt: completed(2 * time.Hour)}},
+	}
+	for _, test := range tests {
+		test.job.ID = uuid.New()
+		out := c.exportJobOutFrom(test.job)
+		if !test.wantLink {
+			if out.DownloadURL != "" || out.ExpiresAt != nil {
+				t.Errorf("%s: expected no link, got %q expiring %v", test.name, out.DownloadURL, out.ExpiresAt)
+			}
+			continue
+		}
+		if out.ExpiresAt == nil || !out.ExpiresAt.Equal(test.job.CompletedAt.Time.Add(c.exportURLTTL)) {
+			t.Errorf("%s: link should expire a TTL after completion, got %v", test.name, out.ExpiresAt)
+		}
+		link, err := url.Parse(out.DownloadURL)
+		if err != nil {
+			t.Errorf("%s: invalid link %q: %s", test.name, out.DownloadURL, err)
+			continue
+		}
+		if link.Path != exportDownloadPath(test.job.ID) {
+			t.Errorf("%s: unexpected path %q", test.name, link.Path)
+		}
+		if err := auth.CheckSignedURL(link.Path, link.Query(), c.exportSecret); err != nil {
+			t.Errorf("%s: link doesn't verify: %s", test.name, err)
+		}
+	}
+}
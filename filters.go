@@ -0,0 +1,102 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"log"
+	"net/http"
+
+	"github.com/cameronbarnes/go_chirpy/internal/chirptext"
+	"github.com/cameronbarnes/go_chirpy/internal/database"
+	"github.com/google/uuid"
+)
+
+func respondWithFilterError(w http.ResponseWriter, err error) {
+	if errors.Is(err, sql.ErrNoRows) {
+		respondWithError(w, 404, "Filter word not found")
+		return
+	}
+	if isUniqueViolation(err) {
+		respondWithError(w, 409, "Word is already filtered")
+		return
+	}
+	log.Printf("Failed to access filter words with err: %s", err)
+	respondWithError(w, 500, "Failed to access filter words")
+}
+
+func (c *apiConfig) getFilterWords(w http.ResponseWriter, r *http.Request) {
+	words, err := c.db.ListFilterWords(context.Background())
+	if err != nil {
+		respondWithFilterError(w, err)
+		return
+	}
+	if words == nil {
+		words = []database.FilterWord{}
+	}
+	respondWithJSON(w, 200, words)
+}
+
+func (c *apiConfig) addFilterWord(w http.ResponseWriter, r *http.Request) {
+	type filterArgs struct {
+		Word   string                 `json:"word"`
+		Action chirptext.FilterAction `json:"action"`
+	}
+	arg, err := handleParse[filterArgs](w, r)
+	if err != nil {
+		return
+	}
+	if !chirptext.ValidFilterWord(arg.Word) {
+		respondWithError(w, 400, "Filter words must be a single word of letters and digits")
+		return
+	}
+	if !arg.Action.Valid() {
+		respondWithError(w, 400, "Action must be mask, reject or flag")
+		return
+	}
+	word, err := c.db.CreateFilterWord(context.Background(), database.CreateFilterWordParams{Word: chirptext.NormalizeFilterWord(arg.Word), Action: string(arg.Action)})
+	if err != nil {
+		respondWithFilterError(w, err)
+		return
+	}
+	respondWithJSON(w, 201, word)
+}
+
+func (c *apiConfig) updateFilterWord(w http.ResponseWriter, r *http.Request) {
+	type filterArgs struct {
+		Action chirptext.FilterAction `json:"action"`
+	}
+	id, err := uuid.Parse(r.PathValue("id"))
+	if err != nil {
+		respondWithError(w, 400, "UUID provided is not valid")
+		return
+	}
+	arg, err := handleParse[filterArgs](w, r)
+	if err != nil {
+		return
+	}
+	if !arg.Action.Valid() {
+		respondWithError(w, 400, "Action must be mask, reject or flag")
+		return
+	}
+	word, err := c.db.UpdateFilterWord(context.Background(), database.UpdateFilterWordParams{ID: id, Action: string(arg.Action)})
+	if err != nil {
+		respondWithFilterError(w, err)
+		return
+	}
+	respondWithJSON(w, 200, word)
+}
+
+func (c *apiConfig) deleteFilterWord(w http.ResponseWriter, r *http.Request) {
+	id, err := uuid.Parse(r.PathValue("id"))
+	if err != nil {
+		respondWithError(w, 400, "UUID provided is not valid")
+		return
+	}
+	_, err = c.db.DeleteFilterWord(context.Background(), id)
+	if err != nil {
+		respondWithFilterError(w, err)
+		return
+	}
+	w.WriteHeader(204)
+}
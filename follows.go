@@ -0,0 +1,48 @@
+package main
+
+import (
+	"context"
+	"log"
+	"net/http"
+
+	"github.com/cameronbarnes/go_chirpy/internal/database"
+	"github.com/google/uuid"
+)
+
+func (c *apiConfig) addFollow(w http.ResponseWriter, r *http.Request, user database.GetUserRow) {
+	id, err := uuid.Parse(r.PathValue("id"))
+	if err != nil {
+		respondWithError(w, 400, "UUID provided is not valid")
+		return
+	}
+	if id == user.ID {
+		respondWithError(w, 400, "You can't follow yourself")
+		return
+	}
+	err = c.db.AddFollow(context.Background(), database.AddFollowParams{FollowerID: user.ID, FolloweeID: id})
+	if isForeignKeyViolation(err) {
+		respondWithError(w, 404, "User Not Found")
+		return
+	}
+	if err != nil {
+		log.Printf("Failed to follow user with err: %s", err)
+		respondWithError(w, 500, "Failed to follow User")
+		return
+	}
+	w.WriteHeader(204)
+}
+
+func (c *apiConfig) deleteFollow(w http.ResponseWriter, r *http.Request, user database.GetUserRow) {
+	id, err := uuid.Parse(r.PathValue("id"))
+	if err != nil {
+		respondWithError(w, 400, "UUID provided is not valid")
+		return
+	}
+	err = c.db.DeleteFollow(context.Background(), database.DeleteFollowParams{FollowerID: user.ID, FolloweeID: id})
+	if err != nil {
+		log.Printf("Failed to unfollow user with err: %s", err)
+		respondWithError(w, 500, "Failed to unfollow User")
+		return
+	}
+	w.WriteHeader(204)
+}
@@ -0,0 +1,40 @@
+package main
+
+import (
+	"context"
+	"log"
+	"net/http"
+
+	"github.com/cameronbarnes/go_chirpy/internal/chirptext"
+	"github.com/cameronbarnes/go_chirpy/internal/database"
+)
+
+func saveHashtags(q *database.Queries, chirp database.Chirp) error {
+	for _, tag := range chirptext.Hashtags(chirp.Body) {
+		err := q.AddChirpHashtag(context.Background(), database.AddChirpHashtagParams{ChirpID: chirp.ID, Tag: tag})
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (c *apiConfig) getHashtagChirps(w http.ResponseWriter, r *http.Request, viewer *database.GetUserRow) {
+	tag := chirptext.NormalizeTag(r.PathValue("tag"))
+	if tag == "" {
+		respondWithError(w, 404, "Hashtag Not Found")
+		return
+	}
+	p, err := parsePage(r)
+	if err != nil {
+		respondWithError(w, 400, err.Error())
+		return
+	}
+	chirps, err := c.db.ListChirpsForHashtag(context.Background(), database.ListChirpsForHashtagParams{Tag: tag, AfterCreatedAt: p.AfterCreatedAt, AfterID: p.AfterID, ViewerID: viewerID(viewer), PageSize: p.fetchLimit()})
+	if err != nil {
+		log.Printf("Failed to get chirps for hashtag with err: %s", err)
+		respondWithError(w, 500, "Failed to get chirps")
+		return
+	}
+	c.respondWithChirps(w, 200, pageChirps(w, r, p, chirps), viewer)
+}
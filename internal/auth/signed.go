@@ -0,0 +1,53 @@
+package auth
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"net/url"
+	"strconv"
+	"time"
+)
+
+var (
+	ErrSignatureInvalid = errors.New("Signature is not valid")
+	ErrSignatureExpired = errors.New("Signature has expired")
+)
+
+// SignURL returns path with expires and signature query parameters, so it
+// can be handed out as a link that works without logging in until expiresAt.
+func SignURL(path, secret string, expiresAt time.Time) string {
+	expires := strconv.FormatInt(expiresAt.Unix(), 10)
+	query := url.Values{}
+	query.Set("expires", expires)
+	query.Set("signature", signature(path, expires, secret))
+	return path + "?" + query.Encode()
+}
+
+// CheckSignedURL checks the query parameters added by SignURL against path.
+func CheckSignedURL(path string, query url.Values, secret string) error {
+	expires := query.Get("expires")
+	sig, err := hex.DecodeString(query.Get("signature"))
+	if err != nil {
+		return ErrSignatureInvalid
+	}
+	want, _ := hex.DecodeString(signature(path, expires, secret))
+	if !hmac.Equal(sig, want) {
+		return ErrSignatureInvalid
+	}
+	unix, err := strconv.ParseInt(expires, 10, 64)
+	if err != nil {
+		return ErrSignatureInvalid
+	}
+	if time.Now().After(time.Unix(unix, 0)) {
+		return ErrSignatureExpired
+	}
+	return nil
+}
+
+func signature(path, expires, secret string) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(path + "\n" + expires))
+	return hex.EncodeToString(mac.Sum(nil))
+}
@@ -0,0 +1,46 @@
+package auth
+
+import (
+	"errors"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+)
+
+func signedQuery(t *testing.T, signed string) url.Values {
+	_, raw, _ := strings.Cut(signed, "?")
+	query, err := url.ParseQuery(raw)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return query
+}
+
+func TestSignedURLValid(t *testing.T) {
+	query := signedQuery(t, SignURL("/api/exports/1/download", "secret", time.Now().Add(time.Hour)))
+	if err := CheckSignedURL("/api/exports/1/download", query, "secret"); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestSignedURLTampered(t *testing.T) {
+	query := signedQuery(t, SignURL("/api/exports/1/download", "secret", time.Now().Add(time.Hour)))
+	if err := CheckSignedURL("/api/exports/2/download", query, "secret"); !errors.Is(err, ErrSignatureInvalid) {
+		t.Errorf("Expected a different path to fail, got: %v", err)
+	}
+	if err := CheckSignedURL("/api/exports/1/download", query, "other"); !errors.Is(err, ErrSignatureInvalid) {
+		t.Errorf("Expected a different secret to fail, got: %v", err)
+	}
+	query.Set("expires", "99999999999")
+	if err := CheckSignedURL("/api/exports/1/download", query, "secret"); !errors.Is(err, ErrSignatureInvalid) {
+		t.Errorf("Expected an extended expiry to fail, got: %v", err)
+	}
+}
+
+func TestSignedURLExpires(t *testing.T) {
+	query := signedQuery(t, SignURL("/api/exports/1/download", "secret", time.Now().Add(-time.Second)))
+	if err := CheckSignedURL("/api/exports/1/download", query, "secret"); !errors.Is(err, ErrSignatureExpired) {
+		t.Errorf("Expected an expired signature, got: %v", err)
+	}
+}
@@ -0,0 +1,104 @@
+package chirptext
+
+import (
+	"unicode"
+	"unicode/utf16"
+	"unicode/utf8"
+)
+
+type EntityType string
+
+const (
+	EntityURL     EntityType = "url"
+	EntityMention EntityType = "mention"
+	EntityHashtag EntityType = "hashtag"
+)
+
+// Entity is a URL, mention or hashtag in a chirp body. Offsets are given both
+// in bytes and in UTF-16 code units, since that's how JavaScript, Swift and
+// Java index strings. Ends are exclusive. Text is the entity as written,
+// sigil included, and Value is its normalized form: the URL itself, or the
+// handle or tag without its sigil.
+type Entity struct {
+	Type       EntityType `json:"type"`
+	Text       string     `json:"text"`
+	Value      string     `json:"value"`
+	ByteStart  int        `json:"byte_start"`
+	ByteEnd    int        `json:"byte_end"`
+	UTF16Start int        `json:"utf16_start"`
+	UTF16End   int        `json:"utf16_end"`
+}
+
+// Entities returns the URLs, mentions and hashtags in body in the order they
+// appear. A URL is read as a whole first, so a '#' or '@' inside one isn't
+// taken for a hashtag or mention. Links, Mentions and Hashtags are all built
+// on this, so they always agree with it.
+func Entities(body string) []Entity {
+	entities := []Entity{}
+	pos := 0
+	for i := 0; i < len(body); {
+		if linkStart(body, i) {
+			end := i
+			for end < len(body) {
+				r, size := utf8.DecodeRuneInString(body[end:])
+				if unicode.IsSpace(r) {
+					break
+				}
+				end += size
+			}
+			link := trimLink(body[i:end])
+			if !isBareScheme(link) {
+				entities = append(entities, newEntity(EntityURL, link, link, i, pos))
+			}
+			pos += utf16Len(body[i:end])
+			i = end
+			continue
+		}
+		r, size := utf8.DecodeRuneInString(body[i:])
+		if (r == '#' || r == '@') && boundaryBefore(body, i) {
+			end := scanWord(body, i+size)
+			word := body[i+size : end]
+			switch {
+			case r == '#' && validTag(word):
+				entities = append(entities, newEntity(EntityHashtag, body[i:end], NormalizeTag(word), i, pos))
+			case r == '@' && ValidHandle(word):
+				entities = append(entities, newEntity(EntityMention, body[i:end], NormalizeHandle(word), i, pos))
+			}
+			pos += utf16Len(body[i:end])
+			i = end
+			continue
+		}
+		pos += utf16RuneLen(r)
+		i += size
+	}
+	return entities
+}
+
+func newEntity(kind EntityType, text, value string, byteStart, utf16Start int) Entity {
+	return Entity{
+		Type:       kind,
+		Text:       text,
+		Value:      value,
+		ByteStart:  byteStart,
+		ByteEnd:    byteStart + len(text),
+		UTF16Start: utf16Start,
+		UTF16End:   utf16Start + utf16Len(text),
+	}
+}
+
+func utf16Len(s string) int {
+	n := 0
+	for _, r := range s {
+		n += utf16RuneLen(r)
+	}
+	return n
+}
+
+// utf16RuneLen counts invalid UTF-8 as the single replacement character a
+// client would decode it to.
+func utf16RuneLen(r rune) int {
+	if n := utf16.RuneLen(r); n > 0 {
+		return n
+	}
+	return 1
+}
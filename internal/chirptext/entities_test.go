@@ -0,0 +1,34 @@
+package chirptext
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestEntitiesOffsets(t *testing.T) {
+	body := "héllo @Alice 😀 #Go https://go.dev/x."
+	entities := Entities(body)
+	want := []Entity{
+		{Type: EntityMention, Text: "@Alice", Value: "alice", ByteStart: 7, ByteEnd: 13, UTF16Start: 6, UTF16End: 12},
+		{Type: EntityHashtag, Text: "#Go", Value: "go", ByteStart: 19, ByteEnd: 22, UTF16Start: 16, UTF16End: 19},
+		{Type: EntityURL, Text: "https://go.dev/x", Value: "https://go.dev/x", ByteStart: 23, ByteEnd: 39, UTF16Start: 20, UTF16End: 36},
+	}
+	if !slices.Equal(entities, want) {
+		t.Fatalf("Unexpected entities: %+v", entities)
+	}
+	for _, entity := range entities {
+		if body[entity.ByteStart:entity.ByteEnd] != entity.Text {
+			t.Errorf("Byte offsets of %q point at %q", entity.Text, body[entity.ByteStart:entity.ByteEnd])
+		}
+	}
+}
+
+func TestEntitiesSkipInsideLinks(t *testing.T) {
+	entities := Entities("https://example.com/#top?by=@someone")
+	if len(entities) != 1 || entities[0].Type != EntityURL {
+		t.Errorf("Expected a single URL, got: %+v", entities)
+	}
+	if tags := Hashtags("https://example.com/#top #real"); !slices.Equal(tags, []string{"real"}) {
+		t.Errorf("Unexpected tags: %v", tags)
+	}
+}
@@ -0,0 +1,144 @@
+package chirptext
+
+import (
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+const maxFilterWordLength = 100
+
+type FilterAction string
+
+const (
+	FilterMask   FilterAction = "mask"
+	FilterReject FilterAction = "reject"
+	FilterFlag   FilterAction = "flag"
+)
+
+func (a FilterAction) Valid() bool {
+	return a == FilterMask || a == FilterReject || a == FilterFlag
+}
+
+type FilterResult struct {
+	Body     string
+	Rejected bool
+	Flagged  bool
+}
+
+// Filter looks up every word of body in rules, which maps words normalized
+// with NormalizeFilterWord to what should happen when they're used. Words are
+// compared by their MatchKey, so look-alike letters, leetspeak and invisible
+// characters don't hide them, and punctuation next to a word doesn't either.
+// Masking only replaces the word itself, leaving the rest of body as written.
+func Filter(body string, rules map[string]FilterAction) FilterResult {
+	out := FilterResult{}
+	var sb strings.Builder
+	for i := 0; i < len(body); {
+		r, size := utf8.DecodeRuneInString(body[i:])
+		if !isTokenRune(r) {
+			sb.WriteString(body[i : i+size])
+			i += size
+			continue
+		}
+		end := i
+		for end < len(body) {
+			r, size := utf8.DecodeRuneInString(body[end:])
+			if !isTokenRune(r) {
+				break
+			}
+			end += size
+		}
+		filterToken(&sb, &out, body[i:end], rules)
+		i = end
+	}
+	out.Body = sb.String()
+	return out
+}
+
+// filterToken writes token to sb after applying any rule it matches. A token
+// may carry symbols that could be leetspeak, like "$harbert", or plain
+// punctuation, like "kerfuffle!", so it's tried as a whole, then without the
+// symbols at either end, and finally piece by piece between symbols.
+func filterToken(sb *strings.Builder, out *FilterResult, token string, rules map[string]FilterAction) {
+	if applyRule(sb, out, token, rules) {
+		return
+	}
+	core := strings.TrimFunc(token, isLeetSymbol)
+	start := strings.Index(token, core)
+	sb.WriteString(token[:start])
+	suffix := token[start+len(core):]
+	if core != token && core != "" && applyRule(sb, out, core, rules) {
+		sb.WriteString(suffix)
+		return
+	}
+	for core != "" {
+		end := strings.IndexFunc(core, isLeetSymbol)
+		if end < 0 {
+			end = len(core)
+		}
+		if end > 0 && !applyRule(sb, out, core[:end], rules) {
+			sb.WriteString(core[:end])
+		}
+		core = core[end:]
+		if core != "" {
+			_, size := utf8.DecodeRuneInString(core)
+			sb.WriteString(core[:size])
+			core = core[size:]
+		}
+	}
+	sb.WriteString(suffix)
+}
+
+// applyRule writes word to sb and reports true if any reading of it matches a
+// rule. When it doesn't match nothing is written.
+func applyRule(sb *strings.Builder, out *FilterResult, word string, rules map[string]FilterAction) bool {
+	var action FilterAction
+	for _, key := range MatchKeys(word) {
+		if action = rules[key]; action != "" {
+			break
+		}
+	}
+	switch action {
+	case FilterMask:
+		sb.WriteString("****")
+	case FilterReject:
+		out.Rejected = true
+		sb.WriteString(word)
+	case FilterFlag:
+		out.Flagged = true
+		sb.WriteString(word)
+	default:
+		return false
+	}
+	return true
+}
+
+// NormalizeFilterWord returns the form a filtered word is stored and compared in.
+func NormalizeFilterWord(word string) string {
+	return MatchKey(word)
+}
+
+// ValidFilterWord reports whether word could ever match, which rules out
+// empty strings and anything Filter would split into several words.
+func ValidFilterWord(word string) bool {
+	if word == "" || utf8.RuneCountInString(word) > maxFilterWordLength {
+		return false
+	}
+	return strings.IndexFunc(word, func(r rune) bool { return !isFilterRune(r) }) < 0
+}
+
+func isTokenRune(r rune) bool {
+	return isFilterRune(r) || isInvisible(r) || isLeetSymbol(r)
+}
+
+func isLeetSymbol(r rune) bool {
+	_, ok := leet[r]
+	return ok && !unicode.IsDigit(r)
+}
+
+// isFilterRune differs from isWordRune in treating '_' as a separator, so
+// wrapping a word in underscores doesn't get it past the filter.
+func isFilterRune(r rune) bool {
+	return unicode.IsLetter(r) || unicode.IsMark(r) || unicode.IsDigit(r)
+}
@@ -0,0 +1,93 @@
+package chirptext
+
+import "testing"
+
+var testRules = map[string]FilterAction{
+	"kerfuffle": FilterMask,
+	"sharbert":  FilterReject,
+	"fornax":    FilterFlag,
+}
+
+func TestFilterMasksThroughPunctuation(t *testing.T) {
+	got := Filter("What a Kerfuffle! (kerfuffle) _kerfuffle_", testRules)
+	if got.Body != "What a ****! (****) _****_" {
+		t.Errorf("Unexpected body: %q", got.Body)
+	}
+	if got.Rejected || got.Flagged {
+		t.Errorf("Masking should not reject or flag: %+v", got)
+	}
+}
+
+func TestFilterPreservesWhitespace(t *testing.T) {
+	got := Filter("a  kerfuffle\n\tb ", testRules)
+	if got.Body != "a  ****\n\tb " {
+		t.Errorf("Whitespace should be kept as written, got %q", got.Body)
+	}
+}
+
+func TestFilterRejectAndFlag(t *testing.T) {
+	got := Filter("SHARBERT, fornax.", testRules)
+	if !got.Rejected || !got.Flagged {
+		t.Errorf("Expected rejected and flagged: %+v", got)
+	}
+	if got.Body != "SHARBERT, fornax." {
+		t.Errorf("Reject and flag should not change the body, got %q", got.Body)
+	}
+}
+
+func TestFilterIgnoresPartialWords(t *testing.T) {
+	got := Filter("kerfuffles fornaxes", testRules)
+	if got.Body != "kerfuffles fornaxes" || got.Flagged {
+		t.Errorf("Only whole words should match: %+v", got)
+	}
+}
+
+func TestValidFilterWord(t *testing.T) {
+	for _, word := range []string{"kerfuffle", "Café", "東京"} {
+		if !ValidFilterWord(word) {
+			t.Errorf("%q should be valid", word)
+		}
+	}
+	for _, word := range []string{"", "two words", "bad!", "snake_case"} {
+		if ValidFilterWord(word) {
+			t.Errorf("%q should not be valid", word)
+		}
+	}
+}
+
+func TestFilterCatchesObfuscation(t *testing.T) {
+	for _, body := range []string{
+		"k3rfuffl3",
+		"KÉRFUFFLE",
+		"k\u0435rfuffl\u0435", // Cyrillic е
+		"ker\u200bfuffle",
+		"\u202ekerfuffle\u202c",
+		"\uff4b\uff45\uff52\uff46\uff55\uff46\uff46\uff4c\uff45",
+		"kerfuff|e",
+		"kerfuff1e",
+		"kerfuffIe", // capital i
+	} {
+		got := Filter("oh "+body+" no", testRules)
+		if got.Body != "oh **** no" {
+			t.Errorf("Filter(%q) = %q, expected the word to be masked", body, got.Body)
+		}
+	}
+}
+
+func TestFilterLeetSymbolsAndPunctuation(t *testing.T) {
+	got := Filter("$harbert @kerfuffle kerfuffle!! kerfuffle!fornax", testRules)
+	if got.Body != "$harbert @**** ****!! ****!fornax" {
+		t.Errorf("Unexpected body: %q", got.Body)
+	}
+	if !got.Rejected || !got.Flagged {
+		t.Errorf("Expected rejected and flagged: %+v", got)
+	}
+}
+
+func TestFilterTriesEveryReadingOfAmbiguousCharacters(t *testing.T) {
+	rules := map[string]FilterAction{"hell": FilterMask, "idiot": FilterMask}
+	got := Filter("He11 HeII 1diot |diot heil", rules)
+	if got.Body != "**** **** **** **** heil" {
+		t.Errorf("Unexpected body: %q", got.Body)
+	}
+}
@@ -0,0 +1,73 @@
+package chirptext
+
+import (
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"golang.org/x/text/cases"
+	"golang.org/x/text/unicode/norm"
+)
+
+const maxTagLength = 100
+
+// Hashtags returns the distinct tags in body, normalized by NormalizeTag, in
+// the order they first appear and without the leading '#'.
+func Hashtags(body string) []string {
+	return distinctValues(body, EntityHashtag)
+}
+
+// NormalizeTag turns a user supplied tag, with or without its '#', into the
+// form stored in the database. Tags are case folded, so #Go and #go or
+// #Straße and #STRASSE match, and composed, so an accent typed as a separate
+// combining mark matches the precomposed letter.
+func NormalizeTag(tag string) string {
+	return norm.NFC.String(cases.Fold().String(strings.TrimPrefix(tag, "#")))
+}
+
+func isWordRune(r rune) bool {
+	return unicode.IsLetter(r) || unicode.IsMark(r) || unicode.IsDigit(r) || r == '_'
+}
+
+// boundaryBefore reports whether the sigil at index i starts a new token, so
+// that "a#b" or "##b" are not picked up as tags.
+func boundaryBefore(body string, i int) bool {
+	if i == 0 {
+		return true
+	}
+	prev, _ := utf8.DecodeLastRuneInString(body[:i])
+	return !isWordRune(prev) && prev != '#' && prev != '@'
+}
+
+func scanWord(body string, start int) int {
+	end := start
+	for end < len(body) {
+		r, size := utf8.DecodeRuneInString(body[end:])
+		if !isWordRune(r) {
+			break
+		}
+		end += size
+	}
+	return end
+}
+
+// distinctValues returns the values of the entities of one type in body,
+// each once, in the order they first appear.
+func distinctValues(body string, kind EntityType) []string {
+	values := []string{}
+	seen := map[string]bool{}
+	for _, entity := range Entities(body) {
+		if entity.Type == kind && !seen[entity.Value] {
+			seen[entity.Value] = true
+			values = append(values, entity.Value)
+		}
+	}
+	return values
+}
+
+func validTag(tag string) bool {
+	if tag == "" || utf8.RuneCountInString(tag) > maxTagLength {
+		return false
+	}
+	return strings.IndexFunc(tag, unicode.IsLetter) >= 0
+}
@@ -0,0 +1,44 @@
+package chirptext
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestHashtagsBasic(t *testing.T) {
+	tags := Hashtags("Loving #Go and #golang today")
+	if !slices.Equal(tags, []string{"go", "golang"}) {
+		t.Errorf("Unexpected tags: %v", tags)
+	}
+}
+
+func TestHashtagsCaseInsensitiveDedupe(t *testing.T) {
+	tags := Hashtags("#Chirpy #CHIRPY #chirpy")
+	if !slices.Equal(tags, []string{"chirpy"}) {
+		t.Errorf("Tags should be deduplicated ignoring case, got: %v", tags)
+	}
+}
+
+func TestHashtagsUnicode(t *testing.T) {
+	tags := Hashtags("#Café, #Ωmega! #東京")
+	if !slices.Equal(tags, []string{"café", "ωmega", "東京"}) {
+		t.Errorf("Unexpected tags: %v", tags)
+	}
+}
+
+func TestHashtagsUnicodeNormalization(t *testing.T) {
+	tags := Hashtags("#Caf\u00e9 #Cafe\u0301 #Straße #STRASSE")
+	if !slices.Equal(tags, []string{"caf\u00e9", "strasse"}) {
+		t.Errorf("Composed and decomposed forms, and case folds, should match, got: %q", tags)
+	}
+	if NormalizeTag("#CAFE\u0301") != "caf\u00e9" {
+		t.Errorf("Unexpected normalized tag: %q", NormalizeTag("#CAFE\u0301"))
+	}
+}
+
+func TestHashtagsIgnoresNonTags(t *testing.T) {
+	tags := Hashtags("issue#12 #123 ##double # alone")
+	if len(tags) != 0 {
+		t.Errorf("Should not have found tags, got: %v", tags)
+	}
+}
@@ -0,0 +1,10 @@
+package chirptext
+
+import "github.com/rivo/uniseg"
+
+// Length counts user-perceived characters. An emoji built from several code
+// points, or a letter with combining accents, counts once, matching what
+// people see when they type.
+func Length(body string) int {
+	return uniseg.GraphemeClusterCount(body)
+}
@@ -0,0 +1,24 @@
+package chirptext
+
+import "testing"
+
+func TestLength(t *testing.T) {
+	tests := []struct {
+		body string
+		want int
+	}{
+		{"", 0},
+		{"hello", 5},
+		{"héllo", 5},
+		{"héllo", 5},
+		{"👍🏽", 1},
+		{"👩‍👩‍👧‍👦 family", 8},
+		{"🇨🇦", 1},
+		{"日本語", 3},
+	}
+	for _, tt := range tests {
+		if got := Length(tt.body); got != tt.want {
+			t.Errorf("Length(%q) = %d, expected %d", tt.body, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,59 @@
+package chirptext
+
+import (
+	"strings"
+	"unicode/utf8"
+)
+
+var linkSchemes = []string{"https://", "http://"}
+
+// Links returns the http and https URLs in body in the order they appear.
+// Trailing punctuation is left off, so "see https://example.com." yields the
+// URL without the full stop, unless it closes a bracket opened in the URL.
+func Links(body string) []string {
+	links := []string{}
+	for _, entity := range Entities(body) {
+		if entity.Type == EntityURL {
+			links = append(links, entity.Value)
+		}
+	}
+	return links
+}
+
+func linkStart(body string, i int) bool {
+	rest := body[i:]
+	for _, scheme := range linkSchemes {
+		if len(rest) >= len(scheme) && strings.EqualFold(rest[:len(scheme)], scheme) {
+			if i == 0 {
+				return true
+			}
+			prev, _ := utf8.DecodeLastRuneInString(body[:i])
+			return !isWordRune(prev)
+		}
+	}
+	return false
+}
+
+func trimLink(link string) string {
+	for link != "" {
+		last, size := utf8.DecodeLastRuneInString(link)
+		switch {
+		case last == ')' && strings.Count(link, "(") >= strings.Count(link, ")"):
+			return link
+		case strings.ContainsRune(".,!?:;)'\"", last):
+			link = link[:len(link)-size]
+		default:
+			return link
+		}
+	}
+	return link
+}
+
+func isBareScheme(link string) bool {
+	for _, scheme := range linkSchemes {
+		if strings.EqualFold(link, scheme) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,27 @@
+package chirptext
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestLinks(t *testing.T) {
+	links := Links("Read https://example.com/a?b=c, then http://a.io.")
+	if !slices.Equal(links, []string{"https://example.com/a?b=c", "http://a.io"}) {
+		t.Errorf("Unexpected links: %v", links)
+	}
+}
+
+func TestLinksKeepsBalancedParens(t *testing.T) {
+	links := Links("(see https://en.wikipedia.org/wiki/Go_(programming_language))")
+	if !slices.Equal(links, []string{"https://en.wikipedia.org/wiki/Go_(programming_language)"}) {
+		t.Errorf("Unexpected links: %v", links)
+	}
+}
+
+func TestLinksIgnoresNonLinks(t *testing.T) {
+	links := Links("xhttps://example.com https:// ftp://example.com")
+	if len(links) != 0 {
+		t.Errorf("Expected no links, got: %v", links)
+	}
+}
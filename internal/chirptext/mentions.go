@@ -0,0 +1,36 @@
+package chirptext
+
+import (
+	"strings"
+)
+
+const (
+	minHandleLength = 3
+	maxHandleLength = 30
+)
+
+// Mentions returns the distinct, normalized handles mentioned in body in the
+// order they first appear. Whether a handle belongs to a user is left to the
+// caller.
+func Mentions(body string) []string {
+	return distinctValues(body, EntityMention)
+}
+
+func NormalizeHandle(handle string) string {
+	return strings.ToLower(strings.TrimPrefix(handle, "@"))
+}
+
+// ValidHandle reports whether handle, without its '@', can be claimed by a
+// user. Handles are limited to ASCII so look-alike letters can't be used to
+// impersonate someone.
+func ValidHandle(handle string) bool {
+	if len(handle) < minHandleLength || len(handle) > maxHandleLength {
+		return false
+	}
+	for _, r := range handle {
+		if !(r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' || r == '_') {
+			return false
+		}
+	}
+	return true
+}
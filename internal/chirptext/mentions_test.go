@@ -0,0 +1,29 @@
+package chirptext
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestMentionsBasic(t *testing.T) {
+	handles := Mentions("Hey @Alice and @bob_2, meet @alice")
+	if !slices.Equal(handles, []string{"alice", "bob_2"}) {
+		t.Errorf("Unexpected handles: %v", handles)
+	}
+}
+
+func TestMentionsIgnoresEmailsAndInvalid(t *testing.T) {
+	handles := Mentions("mail me at someone@example.com or @x or @ünicode")
+	if len(handles) != 0 {
+		t.Errorf("Should not have found handles, got: %v", handles)
+	}
+}
+
+func TestValidHandle(t *testing.T) {
+	if !ValidHandle("chirpy_fan") {
+		t.Errorf("chirpy_fan should be a valid handle")
+	}
+	if ValidHandle("no spaces") || ValidHandle("ab") {
+		t.Errorf("Invalid handles were accepted")
+	}
+}
@@ -0,0 +1,112 @@
+package chirptext
+
+import (
+	"strings"
+	"unicode"
+
+	"golang.org/x/text/unicode/norm"
+)
+
+// confusables maps letters from other scripts that look like Latin letters,
+// compared after lower-casing.
+var confusables = map[rune]rune{
+	// Cyrillic
+	'а': 'a', 'в': 'b', 'г': 'r', 'д': 'd', 'е': 'e', 'ё': 'e', 'з': '3', 'и': 'u',
+	'к': 'k', 'м': 'm', 'н': 'h', 'о': 'o', 'п': 'n', 'р': 'p', 'с': 'c', 'т': 't',
+	'у': 'y', 'х': 'x', 'ь': 'b', 'ѕ': 's', 'і': 'i', 'ї': 'i', 'ј': 'j', 'ԁ': 'd',
+	'ԛ': 'q', 'ԝ': 'w', 'ӏ': 'l',
+	// Greek
+	'α': 'a', 'β': 'b', 'γ': 'y', 'ε': 'e', 'ζ': 'z', 'η': 'n', 'ι': 'i', 'κ': 'k',
+	'μ': 'u', 'ν': 'v', 'ο': 'o', 'ρ': 'p', 'τ': 't', 'υ': 'u', 'χ': 'x', 'ω': 'w',
+	// Latin letters that are easy to mistake for others
+	'ı': 'i', 'ȷ': 'j', 'ɡ': 'g', 'ɑ': 'a', 'ℓ': 'l',
+}
+
+// leet maps the digits and symbols commonly swapped in for letters.
+var leet = map[rune]rune{
+	'0': 'o', '1': 'i', '3': 'e', '4': 'a', '5': 's', '7': 't', '8': 'b', '9': 'g',
+	'@': 'a', '$': 's', '!': 'i', '|': 'l',
+}
+
+// ambiguous maps the characters that could stand for more than one letter to
+// each of them, most likely first. Capital I is here because in many fonts
+// it's indistinguishable from a lower case l.
+var ambiguous = map[rune][]rune{
+	'1': {'i', 'l'}, '!': {'i', 'l'}, '|': {'l', 'i'}, 'I': {'i', 'l'},
+}
+
+// maxMatchKeys caps how many readings MatchKeys tries, so a run of ambiguous
+// characters can't blow up. Characters past the cap get their likeliest
+// reading.
+const maxMatchKeys = 64
+
+// MatchKey folds text into the canonical form used when comparing it with
+// the word filter. It is only for matching: the result loses the difference
+// between "kerfuffle", "KÉRFUFFLE", "k3rfuffle" and the same word spelt with
+// Cyrillic look-alikes, so it should never be stored or shown. Characters
+// that could be more than one letter take their likeliest reading.
+func MatchKey(text string) string {
+	var sb strings.Builder
+	for _, candidates := range matchRunes(text) {
+		sb.WriteRune(candidates[0])
+	}
+	return sb.String()
+}
+
+// MatchKeys returns every reading of text, with MatchKey's first, so "he11"
+// can match "hell" as well as "heii".
+func MatchKeys(text string) []string {
+	keys := []string{""}
+	for _, candidates := range matchRunes(text) {
+		if len(keys)*len(candidates) > maxMatchKeys {
+			candidates = candidates[:1]
+		}
+		next := make([]string, 0, len(keys)*len(candidates))
+		for _, key := range keys {
+			for _, r := range candidates {
+				next = append(next, key+string(r))
+			}
+		}
+		keys = next
+	}
+	return keys
+}
+
+// matchRunes folds text for matching, giving the letters each character
+// could be read as.
+func matchRunes(text string) [][]rune {
+	text = norm.NFC.String(text)
+	out := [][]rune{}
+	for _, r := range text {
+		if isInvisible(r) {
+			continue
+		}
+		// Compatibility decomposition splits off accents and turns full-width
+		// and other styled letters back into plain ones.
+		for _, d := range norm.NFKD.String(string(r)) {
+			if unicode.IsMark(d) {
+				continue
+			}
+			if candidates, ok := ambiguous[d]; ok {
+				out = append(out, candidates)
+				continue
+			}
+			d = unicode.ToLower(d)
+			if folded, ok := confusables[d]; ok {
+				d = folded
+			}
+			if folded, ok := leet[d]; ok {
+				d = folded
+			}
+			out = append(out, []rune{d})
+		}
+	}
+	return out
+}
+
+// isInvisible reports whether r is a format character such as a zero-width
+// space or joiner, or a bidirectional control, which don't render on their
+// own but can be used to break a word up.
+func isInvisible(r rune) bool {
+	return unicode.Is(unicode.Cf, r)
+}
@@ -0,0 +1,34 @@
+package chirptext
+
+import "testing"
+
+func TestMatchKey(t *testing.T) {
+	tests := []struct {
+		text string
+		want string
+	}{
+		{"Kerfuffle", "kerfuffle"},
+		{"k3rfuff1e", "kerfuffie"},
+		{"\u0455h\u0430rb\u0435rt", "sharbert"},
+		{"fo\u200drnax", "fornax"},
+		{"\u2066fornax\u2069", "fornax"},
+		{"Caf\u00e9", "cafe"},
+		{"Cafe\u0301", "cafe"},
+		{"\uff46\uff4f\uff52\uff4e\uff41\uff58", "fornax"},
+	}
+	for _, tt := range tests {
+		if got := MatchKey(tt.text); got != tt.want {
+			t.Errorf("MatchKey(%q) = %q, expected %q", tt.text, got, tt.want)
+		}
+	}
+}
+
+func TestMatchKeysCapsReadings(t *testing.T) {
+	keys := MatchKeys("He11")
+	if len(keys) != 4 || keys[0] != MatchKey("He11") {
+		t.Errorf("Unexpected keys: %q", keys)
+	}
+	if keys := MatchKeys("1111111111111111"); len(keys) > maxMatchKeys {
+		t.Errorf("Expected at most %d keys, got %d", maxMatchKeys, len(keys))
+	}
+}
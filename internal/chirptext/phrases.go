@@ -0,0 +1,76 @@
+package chirptext
+
+import (
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+var stopWords = map[string]bool{
+	"a": true, "about": true, "after": true, "all": true, "also": true, "am": true, "an": true, "and": true,
+	"any": true, "are": true, "as": true, "at": true, "be": true, "been": true, "but": true, "by": true,
+	"can": true, "could": true, "did": true, "do": true, "does": true, "for": true, "from": true, "get": true,
+	"got": true, "had": true, "has": true, "have": true, "he": true, "her": true, "him": true, "his": true,
+	"how": true, "i": true, "if": true, "im": true, "in": true, "into": true, "is": true, "it": true,
+	"its": true, "just": true, "like": true, "me": true, "more": true, "my": true, "no": true, "not": true,
+	"now": true, "of": true, "on": true, "one": true, "or": true, "our": true, "out": true, "so": true,
+	"some": true, "than": true, "that": true, "the": true, "their": true, "them": true, "then": true, "there": true,
+	"they": true, "this": true, "to": true, "too": true, "up": true, "us": true, "very": true, "was": true,
+	"we": true, "were": true, "what": true, "when": true, "which": true, "who": true, "why": true, "will": true,
+	"with": true, "would": true, "you": true, "your": true,
+}
+
+// Phrases returns the distinct two-word phrases in body, lower-cased, in the
+// order they first appear. A phrase is two words with only whitespace between
+// them, so punctuation, stop words, numbers and entities such as links all
+// break a phrase up.
+func Phrases(body string) []string {
+	phrases := []string{}
+	seen := map[string]bool{}
+	entities := Entities(body)
+	prev := ""
+	for i := 0; i < len(body); {
+		if len(entities) > 0 && i == entities[0].ByteStart {
+			i = entities[0].ByteEnd
+			entities = entities[1:]
+			prev = ""
+			continue
+		}
+		r, size := utf8.DecodeRuneInString(body[i:])
+		if unicode.IsSpace(r) {
+			i += size
+			continue
+		}
+		if !isWordRune(r) {
+			i += size
+			prev = ""
+			continue
+		}
+		end := scanWord(body, i)
+		if len(entities) > 0 && end > entities[0].ByteStart {
+			end = entities[0].ByteStart
+		}
+		word := strings.ToLower(body[i:end])
+		i = end
+		if !phraseWord(word) {
+			prev = ""
+			continue
+		}
+		if prev != "" {
+			phrase := prev + " " + word
+			if !seen[phrase] {
+				seen[phrase] = true
+				phrases = append(phrases, phrase)
+			}
+		}
+		prev = word
+	}
+	return phrases
+}
+
+func phraseWord(word string) bool {
+	if utf8.RuneCountInString(word) < 2 || stopWords[word] {
+		return false
+	}
+	return strings.IndexFunc(word, unicode.IsLetter) >= 0
+}
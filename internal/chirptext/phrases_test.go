@@ -0,0 +1,22 @@
+package chirptext
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestPhrases(t *testing.T) {
+	phrases := Phrases("The Solar Eclipse is today. Solar eclipse viewing at noon!")
+	want := []string{"solar eclipse", "eclipse viewing"}
+	if !slices.Equal(phrases, want) {
+		t.Errorf("Unexpected phrases: %v", phrases)
+	}
+}
+
+func TestPhrasesBrokenByEntities(t *testing.T) {
+	phrases := Phrases("big news #launch rocket @nasa team https://nasa.gov great views 2024 event")
+	want := []string{"big news", "great views"}
+	if !slices.Equal(phrases, want) {
+		t.Errorf("Unexpected phrases: %v", phrases)
+	}
+}
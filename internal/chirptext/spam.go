@@ -0,0 +1,67 @@
+package chirptext
+
+import (
+	"strings"
+	"unicode"
+)
+
+// SpamScore is a rough measure of how much body looks like spam. Each signal
+// adds to the score, so callers pick a threshold rather than relying on any
+// single rule.
+func SpamScore(body string) int {
+	score := 0
+	links := Links(body)
+	if len(links) > 1 {
+		score += 2 * (len(links) - 1)
+	}
+	if len(Mentions(body)) > 5 {
+		score += 2
+	}
+	if len(Hashtags(body)) > 5 {
+		score++
+	}
+	// URLs are left out of the text checks since they're often long runs of
+	// lower case letters or repeated characters.
+	text := body
+	for _, link := range links {
+		text = strings.Replace(text, link, " ", 1)
+	}
+	if longestRun(text) >= 5 {
+		score++
+	}
+	if shouting(text) {
+		score++
+	}
+	return score
+}
+
+// longestRun is the length of the longest run of one repeated character, as
+// in "freeeeee!!!!!".
+func longestRun(body string) int {
+	longest, run := 0, 0
+	var prev rune
+	for i, r := range body {
+		if i > 0 && r == prev {
+			run++
+		} else {
+			run = 1
+		}
+		prev = r
+		longest = max(longest, run)
+	}
+	return longest
+}
+
+// shouting reports whether most of a reasonably long body is upper case.
+func shouting(body string) bool {
+	letters, upper := 0, 0
+	for _, r := range body {
+		if unicode.IsLetter(r) {
+			letters++
+			if unicode.IsUpper(r) {
+				upper++
+			}
+		}
+	}
+	return letters >= 10 && upper*10 > letters*7
+}
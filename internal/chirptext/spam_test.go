@@ -0,0 +1,17 @@
+package chirptext
+
+import "testing"
+
+func TestSpamScoreOrdinaryChirp(t *testing.T) {
+	if score := SpamScore("Had a lovely walk this morning, see https://example.com"); score != 0 {
+		t.Errorf("Expected 0, got %d", score)
+	}
+}
+
+func TestSpamScoreAddsSignals(t *testing.T) {
+	body := "FREE MONEY NOWWWWW https://a.example https://b.example https://c.example"
+	// Two extra links, a long run and shouting.
+	if score := SpamScore(body); score != 6 {
+		t.Errorf("Expected 6, got %d", score)
+	}
+}
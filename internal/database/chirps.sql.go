@@ -7,23 +7,258 @@ package database
 
 import (
 	"context"
+	"database/sql"
+	"encoding/json"
+	"time"
 
 	"github.com/google/uuid"
+	"github.com/lib/pq"
 )
 
 const addChirp = `-- name: AddChirp :one
-INSERT INTO chirps (id, body, user_id)
-VALUES (gen_random_uuid(), $1, $2)
-RETURNING id, created_at, updated_at, body, user_id
+INSERT INTO chirps (id, created_at, updated_at, body, user_id, parent_id, quote_of, publish_at, published, flagged, visibility, content_warning, sensitive, entities)
+VALUES (
+    gen_random_uuid(),
+    COALESCE($1::timestamp, NOW()),
+    COALESCE($1::timestamp, NOW()),
+    $2,
+    $3,
+    $4,
+    $5,
+    $1::timestamp,
+    $1::timestamp IS NULL,
+    $6,
+    $7,
+    $8,
+    $9,
+    $10
+)
+RETURNING id, created_at, updated_at, body, user_id, search_vector, parent_id, is_tombstone, rechirp_of, quote_of, deleted_at, publish_at, published, flagged, hidden_at, visibility, content_warning, sensitive, entities
 `
 
 type AddChirpParams struct {
-	Body   string    `json:"body"`
-	UserID uuid.UUID `json:"user_id"`
+	PublishAt      sql.NullTime    `json:"publish_at"`
+	Body           string          `json:"body"`
+	UserID         uuid.UUID       `json:"user_id"`
+	ParentID       uuid.NullUUID   `json:"parent_id"`
+	QuoteOf        uuid.NullUUID   `json:"quote_of"`
+	Flagged        bool            `json:"flagged"`
+	Visibility     string          `json:"visibility"`
+	ContentWarning string          `json:"content_warning"`
+	Sensitive      bool            `json:"sensitive"`
+	Entities       json.RawMessage `json:"entities"`
 }
 
 func (q *Queries) AddChirp(ctx context.Context, arg AddChirpParams) (Chirp, error) {
-	row := q.db.QueryRowContext(ctx, addChirp, arg.Body, arg.UserID)
+	row := q.db.QueryRowContext(ctx, addChirp,
+		arg.PublishAt,
+		arg.Body,
+		arg.UserID,
+		arg.ParentID,
+		arg.QuoteOf,
+		arg.Flagged,
+		arg.Visibility,
+		arg.ContentWarning,
+		arg.Sensitive,
+		arg.Entities,
+	)
+	var i Chirp
+	err := row.Scan(
+		&i.ID,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.Body,
+		&i.UserID,
+		&i.SearchVector,
+		&i.ParentID,
+		&i.IsTombstone,
+		&i.RechirpOf,
+		&i.QuoteOf,
+		&i.DeletedAt,
+		&i.PublishAt,
+		&i.Published,
+		&i.Flagged,
+		&i.HiddenAt,
+		&i.Visibility,
+		&i.ContentWarning,
+		&i.Sensitive,
+		&i.Entities,
+	)
+	return i, err
+}
+
+const addRechirp = `-- name: AddRechirp :one
+INSERT INTO chirps (id, body, user_id, rechirp_of)
+VALUES (gen_random_uuid(), '', $1, $2)
+RETURNING id, created_at, updated_at, body, user_id, search_vector, parent_id, is_tombstone, rechirp_of, quote_of, deleted_at, publish_at, published, flagged, hidden_at, visibility, content_warning, sensitive, entities
+`
+
+type AddRechirpParams struct {
+	UserID    uuid.UUID     `json:"user_id"`
+	RechirpOf uuid.NullUUID `json:"rechirp_of"`
+}
+
+func (q *Queries) AddRechirp(ctx context.Context, arg AddRechirpParams) (Chirp, error) {
+	row := q.db.QueryRowContext(ctx, addRechirp, arg.UserID, arg.RechirpOf)
+	var i Chirp
+	err := row.Scan(
+		&i.ID,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.Body,
+		&i.UserID,
+		&i.SearchVector,
+		&i.ParentID,
+		&i.IsTombstone,
+		&i.RechirpOf,
+		&i.QuoteOf,
+		&i.DeletedAt,
+		&i.PublishAt,
+		&i.Published,
+		&i.Flagged,
+		&i.HiddenAt,
+		&i.Visibility,
+		&i.ContentWarning,
+		&i.Sensitive,
+		&i.Entities,
+	)
+	return i, err
+}
+
+const chirpHasReplies = `-- name: ChirpHasReplies :one
+SELECT EXISTS(SELECT 1 FROM chirps WHERE parent_id = $1::uuid)
+`
+
+func (q *Queries) ChirpHasReplies(ctx context.Context, id uuid.UUID) (bool, error) {
+	row := q.db.QueryRowContext(ctx, chirpHasReplies, id)
+	var exists bool
+	err := row.Scan(&exists)
+	return exists, err
+}
+
+const clearChirpFlag = `-- name: ClearChirpFlag :exec
+UPDATE chirps
+SET flagged = false
+WHERE id = $1
+`
+
+func (q *Queries) ClearChirpFlag(ctx context.Context, id uuid.UUID) error {
+	_, err := q.db.ExecContext(ctx, clearChirpFlag, id)
+	return err
+}
+
+const countRechirpsForChirps = `-- name: CountRechirpsForChirps :many
+SELECT rechirp_of, COUNT(*) AS rechirp_count FROM chirps
+WHERE rechirp_of = ANY($1::uuid[]) AND deleted_at IS NULL
+GROUP BY rechirp_of
+`
+
+type CountRechirpsForChirpsRow struct {
+	RechirpOf    uuid.NullUUID `json:"rechirp_of"`
+	RechirpCount int64         `json:"rechirp_count"`
+}
+
+func (q *Queries) CountRechirpsForChirps(ctx context.Context, chirpIds []uuid.UUID) ([]CountRechirpsForChirpsRow, error) {
+	rows, err := q.db.QueryContext(ctx, countRechirpsForChirps, pq.Array(chirpIds))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []CountRechirpsForChirpsRow
+	for rows.Next() {
+		var i CountRechirpsForChirpsRow
+		if err := rows.Scan(&i.RechirpOf, &i.RechirpCount); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const deleteChirp = `-- name: DeleteChirp :one
+UPDATE chirps
+SET deleted_at = NOW(), updated_at = NOW()
+WHERE id = $1 AND user_id = $2 AND deleted_at IS NULL
+RETURNING id
+`
+
+type DeleteChirpParams struct {
+	ID     uuid.UUID `json:"id"`
+	UserID uuid.UUID `json:"user_id"`
+}
+
+func (q *Queries) DeleteChirp(ctx context.Context, arg DeleteChirpParams) (uuid.UUID, error) {
+	row := q.db.QueryRowContext(ctx, deleteChirp, arg.ID, arg.UserID)
+	var id uuid.UUID
+	err := row.Scan(&id)
+	return id, err
+}
+
+const deleteRechirp = `-- name: DeleteRechirp :one
+DELETE FROM chirps
+WHERE user_id = $1 AND rechirp_of = $2
+RETURNING id
+`
+
+type DeleteRechirpParams struct {
+	UserID    uuid.UUID     `json:"user_id"`
+	RechirpOf uuid.NullUUID `json:"rechirp_of"`
+}
+
+func (q *Queries) DeleteRechirp(ctx context.Context, arg DeleteRechirpParams) (uuid.UUID, error) {
+	row := q.db.QueryRowContext(ctx, deleteRechirp, arg.UserID, arg.RechirpOf)
+	var id uuid.UUID
+	err := row.Scan(&id)
+	return id, err
+}
+
+const filterVisibleChirpIDs = `-- name: FilterVisibleChirpIDs :many
+SELECT chirps.id FROM chirps
+WHERE chirps.id = ANY($1::uuid[])
+AND chirp_visible_to(chirps.id, chirps.user_id, chirps.published, chirps.hidden_at, chirps.visibility, $2::uuid)
+`
+
+type FilterVisibleChirpIDsParams struct {
+	Ids      []uuid.UUID `json:"ids"`
+	ViewerID uuid.UUID   `json:"viewer_id"`
+}
+
+func (q *Queries) FilterVisibleChirpIDs(ctx context.Context, arg FilterVisibleChirpIDsParams) ([]uuid.UUID, error) {
+	rows, err := q.db.QueryContext(ctx, filterVisibleChirpIDs, pq.Array(arg.Ids), arg.ViewerID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []uuid.UUID
+	for rows.Next() {
+		var id uuid.UUID
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		items = append(items, id)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getChirp = `-- name: GetChirp :one
+SELECT id, created_at, updated_at, body, user_id, search_vector, parent_id, is_tombstone, rechirp_of, quote_of, deleted_at, publish_at, published, flagged, hidden_at, visibility, content_warning, sensitive, entities FROM chirps
+WHERE id = $1
+`
+
+func (q *Queries) GetChirp(ctx context.Context, id uuid.UUID) (Chirp, error) {
+	row := q.db.QueryRowContext(ctx, getChirp, id)
 	var i Chirp
 	err := row.Scan(
 		&i.ID,
@@ -31,17 +266,39 @@ func (q *Queries) AddChirp(ctx context.Context, arg AddChirpParams) (Chirp, erro
 		&i.UpdatedAt,
 		&i.Body,
 		&i.UserID,
+		&i.SearchVector,
+		&i.ParentID,
+		&i.IsTombstone,
+		&i.RechirpOf,
+		&i.QuoteOf,
+		&i.DeletedAt,
+		&i.PublishAt,
+		&i.Published,
+		&i.Flagged,
+		&i.HiddenAt,
+		&i.Visibility,
+		&i.ContentWarning,
+		&i.Sensitive,
+		&i.Entities,
 	)
 	return i, err
 }
 
-const allChirps = `-- name: AllChirps :many
-SELECT id, created_at, updated_at, body, user_id FROM chirps
-ORDER BY created_at ASC
+const getChirpAncestors = `-- name: GetChirpAncestors :many
+WITH RECURSIVE ancestors AS (
+    SELECT parent.id, parent.parent_id, 1 AS depth FROM chirps parent
+    WHERE parent.id = (SELECT chirps.parent_id FROM chirps WHERE chirps.id = $1)
+    UNION ALL
+    SELECT parent.id, parent.parent_id, ancestors.depth + 1 FROM chirps parent
+    INNER JOIN ancestors ON parent.id = ancestors.parent_id
+)
+SELECT chirps.id, chirps.created_at, chirps.updated_at, chirps.body, chirps.user_id, chirps.search_vector, chirps.parent_id, chirps.is_tombstone, chirps.rechirp_of, chirps.quote_of, chirps.deleted_at, chirps.publish_at, chirps.published, chirps.flagged, chirps.hidden_at, chirps.visibility, chirps.content_warning, chirps.sensitive, chirps.entities FROM chirps
+INNER JOIN ancestors ON chirps.id = ancestors.id
+ORDER BY ancestors.depth DESC
 `
 
-func (q *Queries) AllChirps(ctx context.Context) ([]Chirp, error) {
-	rows, err := q.db.QueryContext(ctx, allChirps)
+func (q *Queries) GetChirpAncestors(ctx context.Context, id uuid.UUID) ([]Chirp, error) {
+	rows, err := q.db.QueryContext(ctx, getChirpAncestors, id)
 	if err != nil {
 		return nil, err
 	}
@@ -55,6 +312,20 @@ func (q *Queries) AllChirps(ctx context.Context) ([]Chirp, error) {
 			&i.UpdatedAt,
 			&i.Body,
 			&i.UserID,
+			&i.SearchVector,
+			&i.ParentID,
+			&i.IsTombstone,
+			&i.RechirpOf,
+			&i.QuoteOf,
+			&i.DeletedAt,
+			&i.PublishAt,
+			&i.Published,
+			&i.Flagged,
+			&i.HiddenAt,
+			&i.Visibility,
+			&i.ContentWarning,
+			&i.Sensitive,
+			&i.Entities,
 		); err != nil {
 			return nil, err
 		}
@@ -69,14 +340,21 @@ func (q *Queries) AllChirps(ctx context.Context) ([]Chirp, error) {
 	return items, nil
 }
 
-const allChirpsFromUser = `-- name: AllChirpsFromUser :many
-SELECT id, created_at, updated_at, body, user_id FROM chirps
-WHERE user_id = $1
-ORDER BY created_at ASC
+const getChirpDescendants = `-- name: GetChirpDescendants :many
+WITH RECURSIVE descendants AS (
+    SELECT reply.id FROM chirps reply
+    WHERE reply.parent_id = $1::uuid
+    UNION ALL
+    SELECT reply.id FROM chirps reply
+    INNER JOIN descendants ON reply.parent_id = descendants.id
+)
+SELECT chirps.id, chirps.created_at, chirps.updated_at, chirps.body, chirps.user_id, chirps.search_vector, chirps.parent_id, chirps.is_tombstone, chirps.rechirp_of, chirps.quote_of, chirps.deleted_at, chirps.publish_at, chirps.published, chirps.flagged, chirps.hidden_at, chirps.visibility, chirps.content_warning, chirps.sensitive, chirps.entities FROM chirps
+INNER JOIN descendants ON chirps.id = descendants.id
+ORDER BY chirps.created_at ASC, chirps.id ASC
 `
 
-func (q *Queries) AllChirpsFromUser(ctx context.Context, userID uuid.UUID) ([]Chirp, error) {
-	rows, err := q.db.QueryContext(ctx, allChirpsFromUser, userID)
+func (q *Queries) GetChirpDescendants(ctx context.Context, id uuid.UUID) ([]Chirp, error) {
+	rows, err := q.db.QueryContext(ctx, getChirpDescendants, id)
 	if err != nil {
 		return nil, err
 	}
@@ -90,6 +368,20 @@ func (q *Queries) AllChirpsFromUser(ctx context.Context, userID uuid.UUID) ([]Ch
 			&i.UpdatedAt,
 			&i.Body,
 			&i.UserID,
+			&i.SearchVector,
+			&i.ParentID,
+			&i.IsTombstone,
+			&i.RechirpOf,
+			&i.QuoteOf,
+			&i.DeletedAt,
+			&i.PublishAt,
+			&i.Published,
+			&i.Flagged,
+			&i.HiddenAt,
+			&i.Visibility,
+			&i.ContentWarning,
+			&i.Sensitive,
+			&i.Entities,
 		); err != nil {
 			return nil, err
 		}
@@ -104,31 +396,583 @@ func (q *Queries) AllChirpsFromUser(ctx context.Context, userID uuid.UUID) ([]Ch
 	return items, nil
 }
 
-const deleteChirp = `-- name: DeleteChirp :one
+const getChirpForUpdate = `-- name: GetChirpForUpdate :one
+SELECT id, created_at, updated_at, body, user_id, search_vector, parent_id, is_tombstone, rechirp_of, quote_of, deleted_at, publish_at, published, flagged, hidden_at, visibility, content_warning, sensitive, entities FROM chirps
+WHERE id = $1
+FOR UPDATE
+`
+
+func (q *Queries) GetChirpForUpdate(ctx context.Context, id uuid.UUID) (Chirp, error) {
+	row := q.db.QueryRowContext(ctx, getChirpForUpdate, id)
+	var i Chirp
+	err := row.Scan(
+		&i.ID,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.Body,
+		&i.UserID,
+		&i.SearchVector,
+		&i.ParentID,
+		&i.IsTombstone,
+		&i.RechirpOf,
+		&i.QuoteOf,
+		&i.DeletedAt,
+		&i.PublishAt,
+		&i.Published,
+		&i.Flagged,
+		&i.HiddenAt,
+		&i.Visibility,
+		&i.ContentWarning,
+		&i.Sensitive,
+		&i.Entities,
+	)
+	return i, err
+}
+
+const getChirpsByIDs = `-- name: GetChirpsByIDs :many
+SELECT id, created_at, updated_at, body, user_id, search_vector, parent_id, is_tombstone, rechirp_of, quote_of, deleted_at, publish_at, published, flagged, hidden_at, visibility, content_warning, sensitive, entities FROM chirps
+WHERE id = ANY($1::uuid[])
+`
+
+func (q *Queries) GetChirpsByIDs(ctx context.Context, ids []uuid.UUID) ([]Chirp, error) {
+	rows, err := q.db.QueryContext(ctx, getChirpsByIDs, pq.Array(ids))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Chirp
+	for rows.Next() {
+		var i Chirp
+		if err := rows.Scan(
+			&i.ID,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.Body,
+			&i.UserID,
+			&i.SearchVector,
+			&i.ParentID,
+			&i.IsTombstone,
+			&i.RechirpOf,
+			&i.QuoteOf,
+			&i.DeletedAt,
+			&i.PublishAt,
+			&i.Published,
+			&i.Flagged,
+			&i.HiddenAt,
+			&i.Visibility,
+			&i.ContentWarning,
+			&i.Sensitive,
+			&i.Entities,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const hideChirp = `-- name: HideChirp :exec
+UPDATE chirps
+SET hidden_at = COALESCE(hidden_at, NOW())
+WHERE id = $1
+`
+
+func (q *Queries) HideChirp(ctx context.Context, id uuid.UUID) error {
+	_, err := q.db.ExecContext(ctx, hideChirp, id)
+	return err
+}
+
+const labelChirp = `-- name: LabelChirp :one
+UPDATE chirps
+SET content_warning = $1, sensitive = $2
+WHERE id = $3
+RETURNING id, created_at, updated_at, body, user_id, search_vector, parent_id, is_tombstone, rechirp_of, quote_of, deleted_at, publish_at, published, flagged, hidden_at, visibility, content_warning, sensitive, entities
+`
+
+type LabelChirpParams struct {
+	ContentWarning string    `json:"content_warning"`
+	Sensitive      bool      `json:"sensitive"`
+	ID             uuid.UUID `json:"id"`
+}
+
+func (q *Queries) LabelChirp(ctx context.Context, arg LabelChirpParams) (Chirp, error) {
+	row := q.db.QueryRowContext(ctx, labelChirp, arg.ContentWarning, arg.Sensitive, arg.ID)
+	var i Chirp
+	err := row.Scan(
+		&i.ID,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.Body,
+		&i.UserID,
+		&i.SearchVector,
+		&i.ParentID,
+		&i.IsTombstone,
+		&i.RechirpOf,
+		&i.QuoteOf,
+		&i.DeletedAt,
+		&i.PublishAt,
+		&i.Published,
+		&i.Flagged,
+		&i.HiddenAt,
+		&i.Visibility,
+		&i.ContentWarning,
+		&i.Sensitive,
+		&i.Entities,
+	)
+	return i, err
+}
+
+const listChirps = `-- name: ListChirps :many
+SELECT id, created_at, updated_at, body, user_id, search_vector, parent_id, is_tombstone, rechirp_of, quote_of, deleted_at, publish_at, published, flagged, hidden_at, visibility, content_warning, sensitive, entities FROM chirps
+WHERE ($1::uuid IS NULL OR user_id = $1::uuid)
+AND ($2::timestamp IS NULL OR created_at >= $2::timestamp)
+AND ($3::timestamp IS NULL OR created_at < $3::timestamp)
+AND ($4::timestamp IS NULL OR (created_at, id) > ($4::timestamp, $5::uuid))
+AND NOT is_tombstone AND deleted_at IS NULL
+AND chirp_visible_to(chirps.id, chirps.user_id, chirps.published, chirps.hidden_at, chirps.visibility, $6::uuid)
+ORDER BY created_at ASC, id ASC
+LIMIT $7
+`
+
+type ListChirpsParams struct {
+	AuthorID       uuid.NullUUID `json:"author_id"`
+	Since          sql.NullTime  `json:"since"`
+	Until          sql.NullTime  `json:"until"`
+	AfterCreatedAt sql.NullTime  `json:"after_created_at"`
+	AfterID        uuid.NullUUID `json:"after_id"`
+	ViewerID       uuid.NullUUID `json:"viewer_id"`
+	PageSize       int32         `json:"page_size"`
+}
+
+func (q *Queries) ListChirps(ctx context.Context, arg ListChirpsParams) ([]Chirp, error) {
+	rows, err := q.db.QueryContext(ctx, listChirps,
+		arg.AuthorID,
+		arg.Since,
+		arg.Until,
+		arg.AfterCreatedAt,
+		arg.AfterID,
+		arg.ViewerID,
+		arg.PageSize,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Chirp
+	for rows.Next() {
+		var i Chirp
+		if err := rows.Scan(
+			&i.ID,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.Body,
+			&i.UserID,
+			&i.SearchVector,
+			&i.ParentID,
+			&i.IsTombstone,
+			&i.RechirpOf,
+			&i.QuoteOf,
+			&i.DeletedAt,
+			&i.PublishAt,
+			&i.Published,
+			&i.Flagged,
+			&i.HiddenAt,
+			&i.Visibility,
+			&i.ContentWarning,
+			&i.Sensitive,
+			&i.Entities,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listChirpsDesc = `-- name: ListChirpsDesc :many
+SELECT id, created_at, updated_at, body, user_id, search_vector, parent_id, is_tombstone, rechirp_of, quote_of, deleted_at, publish_at, published, flagged, hidden_at, visibility, content_warning, sensitive, entities FROM chirps
+WHERE ($1::uuid IS NULL OR user_id = $1::uuid)
+AND ($2::timestamp IS NULL OR created_at >= $2::timestamp)
+AND ($3::timestamp IS NULL OR created_at < $3::timestamp)
+AND ($4::timestamp IS NULL OR (created_at, id) < ($4::timestamp, $5::uuid))
+AND NOT is_tombstone AND deleted_at IS NULL
+AND chirp_visible_to(chirps.id, chirps.user_id, chirps.published, chirps.hidden_at, chirps.visibility, $6::uuid)
+ORDER BY created_at DESC, id DESC
+LIMIT $7
+`
+
+type ListChirpsDescParams struct {
+	AuthorID       uuid.NullUUID `json:"author_id"`
+	Since          sql.NullTime  `json:"since"`
+	Until          sql.NullTime  `json:"until"`
+	AfterCreatedAt sql.NullTime  `json:"after_created_at"`
+	AfterID        uuid.NullUUID `json:"after_id"`
+	ViewerID       uuid.NullUUID `json:"viewer_id"`
+	PageSize       int32         `json:"page_size"`
+}
+
+func (q *Queries) ListChirpsDesc(ctx context.Context, arg ListChirpsDescParams) ([]Chirp, error) {
+	rows, err := q.db.QueryContext(ctx, listChirpsDesc,
+		arg.AuthorID,
+		arg.Since,
+		arg.Until,
+		arg.AfterCreatedAt,
+		arg.AfterID,
+		arg.ViewerID,
+		arg.PageSize,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Chirp
+	for rows.Next() {
+		var i Chirp
+		if err := rows.Scan(
+			&i.ID,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.Body,
+			&i.UserID,
+			&i.SearchVector,
+			&i.ParentID,
+			&i.IsTombstone,
+			&i.RechirpOf,
+			&i.QuoteOf,
+			&i.DeletedAt,
+			&i.PublishAt,
+			&i.Published,
+			&i.Flagged,
+			&i.HiddenAt,
+			&i.Visibility,
+			&i.ContentWarning,
+			&i.Sensitive,
+			&i.Entities,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listPurgeableChirps = `-- name: ListPurgeableChirps :many
+SELECT id FROM chirps
+WHERE deleted_at < $1::timestamp AND NOT is_tombstone
+LIMIT 500
+`
+
+func (q *Queries) ListPurgeableChirps(ctx context.Context, deletedBefore time.Time) ([]uuid.UUID, error) {
+	rows, err := q.db.QueryContext(ctx, listPurgeableChirps, deletedBefore)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []uuid.UUID
+	for rows.Next() {
+		var id uuid.UUID
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		items = append(items, id)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listUnparsedChirps = `-- name: ListUnparsedChirps :many
+SELECT id, body FROM chirps
+WHERE entities = 'null'::jsonb
+LIMIT 500
+`
+
+type ListUnparsedChirpsRow struct {
+	ID   uuid.UUID `json:"id"`
+	Body string    `json:"body"`
+}
+
+func (q *Queries) ListUnparsedChirps(ctx context.Context) ([]ListUnparsedChirpsRow, error) {
+	rows, err := q.db.QueryContext(ctx, listUnparsedChirps)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ListUnparsedChirpsRow
+	for rows.Next() {
+		var i ListUnparsedChirpsRow
+		if err := rows.Scan(&i.ID, &i.Body); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const publishDueChirps = `-- name: PublishDueChirps :many
+UPDATE chirps
+SET published = true, updated_at = NOW()
+WHERE NOT published AND id IN (
+    SELECT due.id FROM chirps due
+    WHERE NOT due.published AND due.publish_at <= NOW()
+    ORDER BY due.publish_at
+    LIMIT 100
+    FOR UPDATE SKIP LOCKED
+)
+RETURNING id, created_at, updated_at, body, user_id, search_vector, parent_id, is_tombstone, rechirp_of, quote_of, deleted_at, publish_at, published, flagged, hidden_at, visibility, content_warning, sensitive, entities
+`
+
+func (q *Queries) PublishDueChirps(ctx context.Context) ([]Chirp, error) {
+	rows, err := q.db.QueryContext(ctx, publishDueChirps)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Chirp
+	for rows.Next() {
+		var i Chirp
+		if err := rows.Scan(
+			&i.ID,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.Body,
+			&i.UserID,
+			&i.SearchVector,
+			&i.ParentID,
+			&i.IsTombstone,
+			&i.RechirpOf,
+			&i.QuoteOf,
+			&i.DeletedAt,
+			&i.PublishAt,
+			&i.Published,
+			&i.Flagged,
+			&i.HiddenAt,
+			&i.Visibility,
+			&i.ContentWarning,
+			&i.Sensitive,
+			&i.Entities,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const purgeChirp = `-- name: PurgeChirp :exec
 DELETE FROM chirps
-WHERE id = $1 AND user_id = $2
-RETURNING id
+WHERE id = $1
 `
 
-type DeleteChirpParams struct {
-	ID     uuid.UUID `json:"id"`
-	UserID uuid.UUID `json:"user_id"`
+func (q *Queries) PurgeChirp(ctx context.Context, id uuid.UUID) error {
+	_, err := q.db.ExecContext(ctx, purgeChirp, id)
+	return err
 }
 
-func (q *Queries) DeleteChirp(ctx context.Context, arg DeleteChirpParams) (uuid.UUID, error) {
-	row := q.db.QueryRowContext(ctx, deleteChirp, arg.ID, arg.UserID)
-	var id uuid.UUID
-	err := row.Scan(&id)
-	return id, err
+const restoreChirp = `-- name: RestoreChirp :one
+UPDATE chirps
+SET deleted_at = NULL, updated_at = NOW()
+WHERE id = $1 AND user_id = $2 AND deleted_at > $3::timestamp
+RETURNING id, created_at, updated_at, body, user_id, search_vector, parent_id, is_tombstone, rechirp_of, quote_of, deleted_at, publish_at, published, flagged, hidden_at, visibility, content_warning, sensitive, entities
+`
+
+type RestoreChirpParams struct {
+	ID           uuid.UUID `json:"id"`
+	UserID       uuid.UUID `json:"user_id"`
+	DeletedAfter time.Time `json:"deleted_after"`
 }
 
-const getChirp = `-- name: GetChirp :one
-SELECT id, created_at, updated_at, body, user_id FROM chirps
+func (q *Queries) RestoreChirp(ctx context.Context, arg RestoreChirpParams) (Chirp, error) {
+	row := q.db.QueryRowContext(ctx, restoreChirp, arg.ID, arg.UserID, arg.DeletedAfter)
+	var i Chirp
+	err := row.Scan(
+		&i.ID,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.Body,
+		&i.UserID,
+		&i.SearchVector,
+		&i.ParentID,
+		&i.IsTombstone,
+		&i.RechirpOf,
+		&i.QuoteOf,
+		&i.DeletedAt,
+		&i.PublishAt,
+		&i.Published,
+		&i.Flagged,
+		&i.HiddenAt,
+		&i.Visibility,
+		&i.ContentWarning,
+		&i.Sensitive,
+		&i.Entities,
+	)
+	return i, err
+}
+
+const searchChirps = `-- name: SearchChirps :many
+SELECT id, created_at, updated_at, body, user_id, search_vector, parent_id, is_tombstone, rechirp_of, quote_of, deleted_at, publish_at, published, flagged, hidden_at, visibility, content_warning, sensitive, entities FROM chirps
+WHERE search_vector @@ websearch_to_tsquery('english', $1::text)
+AND ($2::uuid IS NULL OR user_id = $2::uuid)
+AND ($3::timestamp IS NULL OR created_at >= $3::timestamp)
+AND ($4::timestamp IS NULL OR created_at < $4::timestamp)
+AND NOT is_tombstone AND deleted_at IS NULL
+AND chirp_visible_to(chirps.id, chirps.user_id, chirps.published, chirps.hidden_at, chirps.visibility, $5::uuid)
+ORDER BY ts_rank(search_vector, websearch_to_tsquery('english', $1::text)) DESC, created_at DESC
+LIMIT $6
+`
+
+type SearchChirpsParams struct {
+	Query    string        `json:"query"`
+	AuthorID uuid.NullUUID `json:"author_id"`
+	Since    sql.NullTime  `json:"since"`
+	Until    sql.NullTime  `json:"until"`
+	ViewerID uuid.NullUUID `json:"viewer_id"`
+	PageSize int32         `json:"page_size"`
+}
+
+func (q *Queries) SearchChirps(ctx context.Context, arg SearchChirpsParams) ([]Chirp, error) {
+	rows, err := q.db.QueryContext(ctx, searchChirps,
+		arg.Query,
+		arg.AuthorID,
+		arg.Since,
+		arg.Until,
+		arg.ViewerID,
+		arg.PageSize,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Chirp
+	for rows.Next() {
+		var i Chirp
+		if err := rows.Scan(
+			&i.ID,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.Body,
+			&i.UserID,
+			&i.SearchVector,
+			&i.ParentID,
+			&i.IsTombstone,
+			&i.RechirpOf,
+			&i.QuoteOf,
+			&i.DeletedAt,
+			&i.PublishAt,
+			&i.Published,
+			&i.Flagged,
+			&i.HiddenAt,
+			&i.Visibility,
+			&i.ContentWarning,
+			&i.Sensitive,
+			&i.Entities,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const setChirpEntities = `-- name: SetChirpEntities :exec
+UPDATE chirps
+SET entities = $1
+WHERE id = $2
+`
+
+type SetChirpEntitiesParams struct {
+	Entities json.RawMessage `json:"entities"`
+	ID       uuid.UUID       `json:"id"`
+}
+
+func (q *Queries) SetChirpEntities(ctx context.Context, arg SetChirpEntitiesParams) error {
+	_, err := q.db.ExecContext(ctx, setChirpEntities, arg.Entities, arg.ID)
+	return err
+}
+
+const tombstoneChirp = `-- name: TombstoneChirp :exec
+UPDATE chirps
+SET body = '', entities = '[]', is_tombstone = true, updated_at = NOW()
 WHERE id = $1
 `
 
-func (q *Queries) GetChirp(ctx context.Context, id uuid.UUID) (Chirp, error) {
-	row := q.db.QueryRowContext(ctx, getChirp, id)
+func (q *Queries) TombstoneChirp(ctx context.Context, id uuid.UUID) error {
+	_, err := q.db.ExecContext(ctx, tombstoneChirp, id)
+	return err
+}
+
+const unhideChirp = `-- name: UnhideChirp :exec
+UPDATE chirps
+SET hidden_at = NULL
+WHERE id = $1
+`
+
+func (q *Queries) UnhideChirp(ctx context.Context, id uuid.UUID) error {
+	_, err := q.db.ExecContext(ctx, unhideChirp, id)
+	return err
+}
+
+const updateChirpBody = `-- name: UpdateChirpBody :one
+UPDATE chirps
+SET body = $1, flagged = $2, entities = $3, updated_at = NOW()
+WHERE id = $4
+RETURNING id, created_at, updated_at, body, user_id, search_vector, parent_id, is_tombstone, rechirp_of, quote_of, deleted_at, publish_at, published, flagged, hidden_at, visibility, content_warning, sensitive, entities
+`
+
+type UpdateChirpBodyParams struct {
+	Body     string          `json:"body"`
+	Flagged  bool            `json:"flagged"`
+	Entities json.RawMessage `json:"entities"`
+	ID       uuid.UUID       `json:"id"`
+}
+
+func (q *Queries) UpdateChirpBody(ctx context.Context, arg UpdateChirpBodyParams) (Chirp, error) {
+	row := q.db.QueryRowContext(ctx, updateChirpBody,
+		arg.Body,
+		arg.Flagged,
+		arg.Entities,
+		arg.ID,
+	)
 	var i Chirp
 	err := row.Scan(
 		&i.ID,
@@ -136,6 +980,20 @@ func (q *Queries) GetChirp(ctx context.Context, id uuid.UUID) (Chirp, error) {
 		&i.UpdatedAt,
 		&i.Body,
 		&i.UserID,
+		&i.SearchVector,
+		&i.ParentID,
+		&i.IsTombstone,
+		&i.RechirpOf,
+		&i.QuoteOf,
+		&i.DeletedAt,
+		&i.PublishAt,
+		&i.Published,
+		&i.Flagged,
+		&i.HiddenAt,
+		&i.Visibility,
+		&i.ContentWarning,
+		&i.Sensitive,
+		&i.Entities,
 	)
 	return i, err
 }
@@ -6,17 +6,158 @@ package database
 
 import (
 	"database/sql"
+	"encoding/json"
 	"time"
 
 	"github.com/google/uuid"
 )
 
 type Chirp struct {
+	ID             uuid.UUID       `json:"id"`
+	CreatedAt      time.Time       `json:"created_at"`
+	UpdatedAt      time.Time       `json:"updated_at"`
+	Body           string          `json:"body"`
+	UserID         uuid.UUID       `json:"user_id"`
+	SearchVector   string          `json:"-"`
+	ParentID       uuid.NullUUID   `json:"parent_id"`
+	IsTombstone    bool            `json:"is_tombstone"`
+	RechirpOf      uuid.NullUUID   `json:"rechirp_of"`
+	QuoteOf        uuid.NullUUID   `json:"quote_of"`
+	DeletedAt      sql.NullTime    `json:"deleted_at"`
+	PublishAt      sql.NullTime    `json:"publish_at"`
+	Published      bool            `json:"published"`
+	Flagged        bool            `json:"-"`
+	HiddenAt       sql.NullTime    `json:"hidden_at"`
+	Visibility     string          `json:"visibility"`
+	ContentWarning string          `json:"content_warning"`
+	Sensitive      bool            `json:"sensitive"`
+	Entities       json.RawMessage `json:"entities"`
+}
+
+type ChirpAppeal struct {
+	ChirpID    uuid.UUID    `json:"chirp_id"`
+	CreatedAt  time.Time    `json:"created_at"`
+	UserID     uuid.UUID    `json:"user_id"`
+	Note       string       `json:"note"`
+	Status     string       `json:"status"`
+	ResolvedAt sql.NullTime `json:"resolved_at"`
+}
+
+type ChirpHashtag struct {
+	ChirpID uuid.UUID `json:"chirp_id"`
+	Tag     string    `json:"tag"`
+}
+
+type ChirpLike struct {
+	UserID    uuid.UUID `json:"user_id"`
+	ChirpID   uuid.UUID `json:"chirp_id"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+type ChirpMention struct {
+	ChirpID uuid.UUID `json:"chirp_id"`
+	UserID  uuid.UUID `json:"user_id"`
+}
+
+type ChirpReport struct {
+	ID         uuid.UUID `json:"id"`
+	CreatedAt  time.Time `json:"created_at"`
+	ChirpID    uuid.UUID `json:"chirp_id"`
+	ReporterID uuid.UUID `json:"reporter_id"`
+	Reason     string    `json:"reason"`
+	Note       string    `json:"note"`
+	Status     string    `json:"status"`
+}
+
+type ChirpRevision struct {
+	ID        uuid.UUID `json:"id"`
+	CreatedAt time.Time `json:"created_at"`
+	ChirpID   uuid.UUID `json:"chirp_id"`
+	Body      string    `json:"body"`
+}
+
+type Draft struct {
 	ID        uuid.UUID `json:"id"`
 	CreatedAt time.Time `json:"created_at"`
 	UpdatedAt time.Time `json:"updated_at"`
+	UserID    uuid.UUID `json:"user_id"`
 	Body      string    `json:"body"`
+}
+
+type ExportJob struct {
+	ID          uuid.UUID    `json:"id"`
+	CreatedAt   time.Time    `json:"created_at"`
+	UpdatedAt   time.Time    `json:"updated_at"`
+	UserID      uuid.UUID    `json:"user_id"`
+	Status      string       `json:"status"`
+	Error       string       `json:"error"`
+	CompletedAt sql.NullTime `json:"completed_at"`
+	HeartbeatAt time.Time    `json:"heartbeat_at"`
+}
+
+type FilterWord struct {
+	ID        uuid.UUID `json:"id"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+	Word      string    `json:"word"`
+	Action    string    `json:"action"`
+}
+
+type Follow struct {
+	FollowerID uuid.UUID `json:"follower_id"`
+	FolloweeID uuid.UUID `json:"followee_id"`
+	CreatedAt  time.Time `json:"created_at"`
+}
+
+type Medium struct {
+	ID                uuid.UUID     `json:"id"`
+	CreatedAt         time.Time     `json:"created_at"`
+	UserID            uuid.UUID     `json:"user_id"`
+	ChirpID           uuid.NullUUID `json:"chirp_id"`
+	Position          int32         `json:"position"`
+	Sha256            string        `json:"sha256"`
+	MimeType          string        `json:"mime_type"`
+	SizeBytes         int64         `json:"size_bytes"`
+	Width             int32         `json:"width"`
+	Height            int32         `json:"height"`
+	ThumbnailSha256   string        `json:"thumbnail_sha256"`
+	ThumbnailMimeType string        `json:"thumbnail_mime_type"`
+	AltText           string        `json:"alt_text"`
+}
+
+type ModerationDecision struct {
+	ID        uuid.UUID `json:"id"`
+	CreatedAt time.Time `json:"created_at"`
+	ChirpID   uuid.UUID `json:"chirp_id"`
+	Action    string    `json:"action"`
+	Note      string    `json:"note"`
+}
+
+type PinnedChirp struct {
 	UserID    uuid.UUID `json:"user_id"`
+	ChirpID   uuid.UUID `json:"chirp_id"`
+	Position  int32     `json:"position"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+type Poll struct {
+	ChirpID   uuid.UUID `json:"chirp_id"`
+	CreatedAt time.Time `json:"created_at"`
+	ClosesAt  time.Time `json:"closes_at"`
+}
+
+type PollOption struct {
+	ID       uuid.UUID `json:"id"`
+	ChirpID  uuid.UUID `json:"chirp_id"`
+	Position int32     `json:"position"`
+	Label    string    `json:"label"`
+}
+
+type PollVote struct {
+	ChirpID   uuid.UUID `json:"chirp_id"`
+	UserID    uuid.UUID `json:"user_id"`
+	OptionID  uuid.UUID `json:"option_id"`
+	CreatedAt time.Time `json:"created_at"`
 }
 
 type RefreshToken struct {
@@ -28,11 +169,22 @@ type RefreshToken struct {
 	RevokedAt sql.NullTime `json:"revoked_at"`
 }
 
+type TrendCount struct {
+	Bucket    time.Time `json:"bucket"`
+	Kind      string    `json:"kind"`
+	Term      string    `json:"term"`
+	Count     int32     `json:"count"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
 type User struct {
-	ID             uuid.UUID `json:"id"`
-	CreatedAt      time.Time `json:"created_at"`
-	UpdatedAt      time.Time `json:"updated_at"`
-	Email          string    `json:"email"`
-	HashedPassword string    `json:"hashed_password"`
-	IsChirpyRed    bool      `json:"is_chirpy_red"`
+	ID               uuid.UUID    `json:"id"`
+	CreatedAt        time.Time    `json:"created_at"`
+	UpdatedAt        time.Time    `json:"updated_at"`
+	Email            string       `json:"email"`
+	HashedPassword   string       `json:"hashed_password"`
+	IsChirpyRed      bool         `json:"is_chirpy_red"`
+	Handle           *string      `json:"handle"`
+	SuspendedAt      sql.NullTime `json:"suspended_at"`
+	SensitiveContent string       `json:"sensitive_content"`
 }
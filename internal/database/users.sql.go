@@ -7,38 +7,43 @@ package database
 
 import (
 	"context"
+	"database/sql"
 	"time"
 
 	"github.com/google/uuid"
+	"github.com/lib/pq"
 )
 
 const createUser = `-- name: CreateUser :one
-INSERT INTO users (id, email, hashed_password)
+INSERT INTO users (id, email, hashed_password, handle)
 VALUES (
-    gen_random_uuid(), $1, $2
+    gen_random_uuid(), $1, $2, $3
 )
-RETURNING id, email, created_at, updated_at, is_chirpy_red
+RETURNING id, email, handle, created_at, updated_at, is_chirpy_red
 `
 
 type CreateUserParams struct {
-	Email          string `json:"email"`
-	HashedPassword string `json:"hashed_password"`
+	Email          string  `json:"email"`
+	HashedPassword string  `json:"hashed_password"`
+	Handle         *string `json:"handle"`
 }
 
 type CreateUserRow struct {
 	ID          uuid.UUID `json:"id"`
 	Email       string    `json:"email"`
+	Handle      *string   `json:"handle"`
 	CreatedAt   time.Time `json:"created_at"`
 	UpdatedAt   time.Time `json:"updated_at"`
 	IsChirpyRed bool      `json:"is_chirpy_red"`
 }
 
 func (q *Queries) CreateUser(ctx context.Context, arg CreateUserParams) (CreateUserRow, error) {
-	row := q.db.QueryRowContext(ctx, createUser, arg.Email, arg.HashedPassword)
+	row := q.db.QueryRowContext(ctx, createUser, arg.Email, arg.HashedPassword, arg.Handle)
 	var i CreateUserRow
 	err := row.Scan(
 		&i.ID,
 		&i.Email,
+		&i.Handle,
 		&i.CreatedAt,
 		&i.UpdatedAt,
 		&i.IsChirpyRed,
@@ -56,16 +61,19 @@ func (q *Queries) DeleteAll(ctx context.Context) error {
 }
 
 const getUser = `-- name: GetUser :one
-SELECT id, created_at, updated_at, email, is_chirpy_red FROM users
+SELECT id, created_at, updated_at, email, handle, is_chirpy_red, suspended_at, sensitive_content FROM users
 WHERE id = $1
 `
 
 type GetUserRow struct {
-	ID          uuid.UUID `json:"id"`
-	CreatedAt   time.Time `json:"created_at"`
-	UpdatedAt   time.Time `json:"updated_at"`
-	Email       string    `json:"email"`
-	IsChirpyRed bool      `json:"is_chirpy_red"`
+	ID               uuid.UUID    `json:"id"`
+	CreatedAt        time.Time    `json:"created_at"`
+	UpdatedAt        time.Time    `json:"updated_at"`
+	Email            string       `json:"email"`
+	Handle           *string      `json:"handle"`
+	IsChirpyRed      bool         `json:"is_chirpy_red"`
+	SuspendedAt      sql.NullTime `json:"suspended_at"`
+	SensitiveContent string       `json:"sensitive_content"`
 }
 
 func (q *Queries) GetUser(ctx context.Context, id uuid.UUID) (GetUserRow, error) {
@@ -76,13 +84,16 @@ func (q *Queries) GetUser(ctx context.Context, id uuid.UUID) (GetUserRow, error)
 		&i.CreatedAt,
 		&i.UpdatedAt,
 		&i.Email,
+		&i.Handle,
 		&i.IsChirpyRed,
+		&i.SuspendedAt,
+		&i.SensitiveContent,
 	)
 	return i, err
 }
 
 const getUserFromEmail = `-- name: GetUserFromEmail :one
-SELECT id, created_at, updated_at, email, hashed_password, is_chirpy_red FROM users
+SELECT id, created_at, updated_at, email, hashed_password, is_chirpy_red, handle, suspended_at, sensitive_content FROM users
 WHERE email = $1
 `
 
@@ -96,15 +107,51 @@ func (q *Queries) GetUserFromEmail(ctx context.Context, email string) (User, err
 		&i.Email,
 		&i.HashedPassword,
 		&i.IsChirpyRed,
+		&i.Handle,
+		&i.SuspendedAt,
+		&i.SensitiveContent,
 	)
 	return i, err
 }
 
+const getUsersByHandles = `-- name: GetUsersByHandles :many
+SELECT id, handle FROM users
+WHERE handle = ANY($1::text[])
+`
+
+type GetUsersByHandlesRow struct {
+	ID     uuid.UUID `json:"id"`
+	Handle *string   `json:"handle"`
+}
+
+func (q *Queries) GetUsersByHandles(ctx context.Context, handles []string) ([]GetUsersByHandlesRow, error) {
+	rows, err := q.db.QueryContext(ctx, getUsersByHandles, pq.Array(handles))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetUsersByHandlesRow
+	for rows.Next() {
+		var i GetUsersByHandlesRow
+		if err := rows.Scan(&i.ID, &i.Handle); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const setChirpyRedForUser = `-- name: SetChirpyRedForUser :one
 UPDATE users
 SET is_chirpy_red = $1
 WHERE id = $2
-RETURNING id, email, created_at, updated_at, is_chirpy_red
+RETURNING id, email, handle, created_at, updated_at, is_chirpy_red
 `
 
 type SetChirpyRedForUserParams struct {
@@ -115,6 +162,7 @@ type SetChirpyRedForUserParams struct {
 type SetChirpyRedForUserRow struct {
 	ID          uuid.UUID `json:"id"`
 	Email       string    `json:"email"`
+	Handle      *string   `json:"handle"`
 	CreatedAt   time.Time `json:"created_at"`
 	UpdatedAt   time.Time `json:"updated_at"`
 	IsChirpyRed bool      `json:"is_chirpy_red"`
@@ -126,6 +174,7 @@ func (q *Queries) SetChirpyRedForUser(ctx context.Context, arg SetChirpyRedForUs
 	err := row.Scan(
 		&i.ID,
 		&i.Email,
+		&i.Handle,
 		&i.CreatedAt,
 		&i.UpdatedAt,
 		&i.IsChirpyRed,
@@ -133,33 +182,79 @@ func (q *Queries) SetChirpyRedForUser(ctx context.Context, arg SetChirpyRedForUs
 	return i, err
 }
 
+const setSensitiveContent = `-- name: SetSensitiveContent :exec
+UPDATE users
+SET sensitive_content = $1, updated_at = NOW()
+WHERE id = $2
+`
+
+type SetSensitiveContentParams struct {
+	SensitiveContent string    `json:"sensitive_content"`
+	ID               uuid.UUID `json:"id"`
+}
+
+func (q *Queries) SetSensitiveContent(ctx context.Context, arg SetSensitiveContentParams) error {
+	_, err := q.db.ExecContext(ctx, setSensitiveContent, arg.SensitiveContent, arg.ID)
+	return err
+}
+
+const suspendUser = `-- name: SuspendUser :exec
+UPDATE users
+SET suspended_at = COALESCE(suspended_at, NOW())
+WHERE id = $1
+`
+
+func (q *Queries) SuspendUser(ctx context.Context, id uuid.UUID) error {
+	_, err := q.db.ExecContext(ctx, suspendUser, id)
+	return err
+}
+
+const unsuspendUser = `-- name: UnsuspendUser :exec
+UPDATE users
+SET suspended_at = NULL
+WHERE id = $1
+`
+
+func (q *Queries) UnsuspendUser(ctx context.Context, id uuid.UUID) error {
+	_, err := q.db.ExecContext(ctx, unsuspendUser, id)
+	return err
+}
+
 const updateUser = `-- name: UpdateUser :one
 UPDATE users
-SET email = $1, hashed_password = $2, updated_at = NOW()
-WHERE id = $3
-RETURNING id, email, created_at, updated_at, is_chirpy_red
+SET email = $1, hashed_password = $2, handle = COALESCE($3::text, handle), updated_at = NOW()
+WHERE id = $4
+RETURNING id, email, handle, created_at, updated_at, is_chirpy_red
 `
 
 type UpdateUserParams struct {
-	Email          string    `json:"email"`
-	HashedPassword string    `json:"hashed_password"`
-	ID             uuid.UUID `json:"id"`
+	Email          string         `json:"email"`
+	HashedPassword string         `json:"hashed_password"`
+	Handle         sql.NullString `json:"handle"`
+	ID             uuid.UUID      `json:"id"`
 }
 
 type UpdateUserRow struct {
 	ID          uuid.UUID `json:"id"`
 	Email       string    `json:"email"`
+	Handle      *string   `json:"handle"`
 	CreatedAt   time.Time `json:"created_at"`
 	UpdatedAt   time.Time `json:"updated_at"`
 	IsChirpyRed bool      `json:"is_chirpy_red"`
 }
 
 func (q *Queries) UpdateUser(ctx context.Context, arg UpdateUserParams) (UpdateUserRow, error) {
-	row := q.db.QueryRowContext(ctx, updateUser, arg.Email, arg.HashedPassword, arg.ID)
+	row := q.db.QueryRowContext(ctx, updateUser,
+		arg.Email,
+		arg.HashedPassword,
+		arg.Handle,
+		arg.ID,
+	)
 	var i UpdateUserRow
 	err := row.Scan(
 		&i.ID,
 		&i.Email,
+		&i.Handle,
 		&i.CreatedAt,
 		&i.UpdatedAt,
 		&i.IsChirpyRed,
@@ -0,0 +1,95 @@
+package media
+
+import (
+	"bytes"
+	"errors"
+	"image"
+	_ "image/gif"
+	"image/jpeg"
+	"image/png"
+	"net/http"
+
+	"golang.org/x/image/draw"
+	_ "golang.org/x/image/webp"
+)
+
+// MaxPixels caps width times height. Decoding needs memory for every pixel,
+// so a tiny file claiming huge dimensions could otherwise exhaust it.
+const MaxPixels = 40_000_000
+
+var (
+	ErrUnsupportedType = errors.New("Only PNG, JPEG, GIF and WebP images are supported")
+	ErrTooManyPixels   = errors.New("Images can be at most 40 megapixels")
+)
+
+var allowedTypes = map[string]bool{
+	"image/png":  true,
+	"image/jpeg": true,
+	"image/gif":  true,
+	"image/webp": true,
+}
+
+type Image struct {
+	MimeType string
+	Width    int
+	Height   int
+}
+
+// Inspect sniffs the type from the file contents rather than trusting the
+// client, then decodes the header to make sure it really is an image of a
+// size that's safe to decode in full.
+func Inspect(data []byte) (Image, error) {
+	mimeType := http.DetectContentType(data)
+	if !allowedTypes[mimeType] {
+		return Image{}, ErrUnsupportedType
+	}
+	cfg, err := decodeConfig(data)
+	if err != nil {
+		return Image{}, err
+	}
+	return Image{MimeType: mimeType, Width: cfg.Width, Height: cfg.Height}, nil
+}
+
+func decodeConfig(data []byte) (image.Config, error) {
+	cfg, _, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		return image.Config{}, err
+	}
+	if cfg.Width <= 0 || cfg.Height <= 0 || int64(cfg.Width)*int64(cfg.Height) > MaxPixels {
+		return image.Config{}, ErrTooManyPixels
+	}
+	return cfg, nil
+}
+
+// Thumbnail scales the image to fit within maxSize on its longest side,
+// keeping its aspect ratio. Smaller images are re-encoded but not enlarged.
+// PNG and GIF sources stay PNG to keep transparency, everything else is JPEG.
+func Thumbnail(data []byte, maxSize int) ([]byte, string, error) {
+	if _, err := decodeConfig(data); err != nil {
+		return nil, "", err
+	}
+	src, format, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return nil, "", err
+	}
+	bounds := src.Bounds()
+	width, height := bounds.Dx(), bounds.Dy()
+	if width > maxSize || height > maxSize {
+		if width >= height {
+			height = max(1, height*maxSize/width)
+			width = maxSize
+		} else {
+			width = max(1, width*maxSize/height)
+			height = maxSize
+		}
+	}
+	dst := image.NewRGBA(image.Rect(0, 0, width, height))
+	draw.CatmullRom.Scale(dst, dst.Bounds(), src, bounds, draw.Over, nil)
+	var out bytes.Buffer
+	if format == "png" || format == "gif" {
+		err = png.Encode(&out, dst)
+		return out.Bytes(), "image/png", err
+	}
+	err = jpeg.Encode(&out, dst, &jpeg.Options{Quality: 80})
+	return out.Bytes(), "image/jpeg", err
+}
@@ -0,0 +1,78 @@
+package media
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"hash/crc32"
+	"image"
+	"image/color"
+	"image/png"
+	"testing"
+)
+
+func testPNG(t *testing.T, width, height int) []byte {
+	img := image.NewRGBA(image.Rect(0, 0, width, height))
+	img.Set(0, 0, color.RGBA{R: 255, A: 255})
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, img); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+func TestInspectPNG(t *testing.T) {
+	info, err := Inspect(testPNG(t, 40, 20))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if info.MimeType != "image/png" || info.Width != 40 || info.Height != 20 {
+		t.Errorf("Unexpected image info: %+v", info)
+	}
+}
+
+func TestInspectRejectsText(t *testing.T) {
+	_, err := Inspect([]byte("definitely not an image"))
+	if err != ErrUnsupportedType {
+		t.Errorf("Expected ErrUnsupportedType, got: %v", err)
+	}
+}
+
+func TestThumbnailKeepsAspectRatio(t *testing.T) {
+	thumb, mimeType, err := Thumbnail(testPNG(t, 800, 400), 200)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if mimeType != "image/png" {
+		t.Errorf("PNG thumbnails should stay PNG, got %s", mimeType)
+	}
+	cfg, err := png.DecodeConfig(bytes.NewReader(thumb))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cfg.Width != 200 || cfg.Height != 100 {
+		t.Errorf("Expected a 200x100 thumbnail, got %dx%d", cfg.Width, cfg.Height)
+	}
+}
+
+// bombPNG is a tiny PNG whose header claims far larger dimensions than the
+// pixel data behind it.
+func bombPNG(t *testing.T, width, height uint32) []byte {
+	data := testPNG(t, 1, 1)
+	// The IHDR chunk follows the 8 byte signature: length, type, then width
+	// and height, with the CRC over type and data after 13 bytes of data.
+	binary.BigEndian.PutUint32(data[16:], width)
+	binary.BigEndian.PutUint32(data[20:], height)
+	binary.BigEndian.PutUint32(data[29:], crc32.ChecksumIEEE(data[12:29]))
+	return data
+}
+
+func TestInspectRejectsDecompressionBomb(t *testing.T) {
+	data := bombPNG(t, 50000, 50000)
+	if _, err := Inspect(data); !errors.Is(err, ErrTooManyPixels) {
+		t.Errorf("Expected ErrTooManyPixels, got: %v", err)
+	}
+	if _, _, err := Thumbnail(data, 200); !errors.Is(err, ErrTooManyPixels) {
+		t.Errorf("Expected Thumbnail to refuse it too, got: %v", err)
+	}
+}
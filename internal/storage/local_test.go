@@ -0,0 +1,58 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+)
+
+func TestLocalStoreRoundTrip(t *testing.T) {
+	store, err := NewLocalStore(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx := context.Background()
+	if err := store.Put(ctx, "abcdef123", strings.NewReader("chirp")); err != nil {
+		t.Fatal(err)
+	}
+	file, err := store.Open(ctx, "abcdef123")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer file.Close()
+	data, err := io.ReadAll(file)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(data) != "chirp" {
+		t.Errorf("Read back %q, expected %q", data, "chirp")
+	}
+}
+
+func TestLocalStoreMissing(t *testing.T) {
+	store, err := NewLocalStore(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = store.Open(context.Background(), "abcdef123")
+	if !errors.Is(err, ErrNotFound) {
+		t.Errorf("Expected ErrNotFound, got: %v", err)
+	}
+	exists, err := store.Exists(context.Background(), "abcdef123")
+	if err != nil || exists {
+		t.Errorf("Missing key should not exist")
+	}
+}
+
+func TestLocalStoreRejectsPaths(t *testing.T) {
+	store, err := NewLocalStore(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = store.Put(context.Background(), "../../etc/passwd", strings.NewReader("nope"))
+	if err == nil {
+		t.Errorf("Keys containing path separators should be rejected")
+	}
+}
@@ -0,0 +1,219 @@
+package trends
+
+import (
+	"math"
+	"sort"
+	"sync"
+	"time"
+)
+
+type Kind string
+
+const (
+	KindHashtag Kind = "hashtag"
+	KindPhrase  Kind = "phrase"
+)
+
+type Term struct {
+	Kind Kind
+	Text string
+}
+
+// Count is how many chirps used a term in the bucket starting at Bucket. Load
+// takes totals, while Flush hands out only the uses added since the last
+// flush.
+type Count struct {
+	Bucket time.Time
+	Term   Term
+	Count  int
+}
+
+// Trend is a term ranked by how much more it's being used in a window than
+// its baseline suggests. Expected is the count the baseline predicts for the
+// window.
+type Trend struct {
+	Term     Term
+	Count    int
+	Expected float64
+	Score    float64
+}
+
+// Aggregator counts term usage in fixed size time buckets. It's safe for
+// concurrent use. Several instances can share totals by each flushing the
+// uses they've seen and loading back the sum.
+type Aggregator struct {
+	mu     sync.Mutex
+	bucket time.Duration
+	retain time.Duration
+	counts map[time.Time]map[Term]int
+	// pending holds the uses added since the last successful flush.
+	pending map[time.Time]map[Term]int
+}
+
+// NewAggregator counts usage in buckets of the given size, keeping them for
+// retain so they can serve as a baseline.
+func NewAggregator(bucket, retain time.Duration) *Aggregator {
+	return &Aggregator{
+		bucket:  bucket,
+		retain:  retain,
+		counts:  map[time.Time]map[Term]int{},
+		pending: map[time.Time]map[Term]int{},
+	}
+}
+
+// Add counts one use of each term at the given time.
+func (a *Aggregator) Add(at time.Time, terms []Term) {
+	if len(terms) == 0 {
+		return
+	}
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	b := at.UTC().Truncate(a.bucket)
+	for _, term := range terms {
+		addCount(a.counts, b, term, 1)
+		addCount(a.pending, b, term, 1)
+	}
+}
+
+// Load replaces the counts with totals saved by every instance, keeping any
+// uses this one hasn't flushed yet on top.
+func (a *Aggregator) Load(totals []Count) {
+	counts := map[time.Time]map[Term]int{}
+	for _, c := range totals {
+		addCount(counts, c.Bucket.UTC().Truncate(a.bucket), c.Term, c.Count)
+	}
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	for b, terms := range a.pending {
+		for term, count := range terms {
+			addCount(counts, b, term, count)
+		}
+	}
+	a.counts = counts
+}
+
+// Merge replaces the counts for the buckets and terms given with totals saved
+// by every instance, keeping unflushed uses on top like Load. Everything else
+// is left alone, so only counts that changed since the last load need to be
+// passed in.
+func (a *Aggregator) Merge(totals []Count) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	for _, c := range totals {
+		b := c.Bucket.UTC().Truncate(a.bucket)
+		if a.counts[b] == nil {
+			a.counts[b] = map[Term]int{}
+		}
+		a.counts[b][c.Term] = c.Count + a.pending[b][c.Term]
+	}
+}
+
+// Flush hands the uses added since the last successful flush to save, which
+// should add them to the stored totals. If save fails they're kept for the
+// next flush.
+func (a *Aggregator) Flush(save func([]Count) error) error {
+	a.mu.Lock()
+	flushed := a.pending
+	a.pending = map[time.Time]map[Term]int{}
+	a.mu.Unlock()
+	out := []Count{}
+	for b, terms := range flushed {
+		for term, count := range terms {
+			out = append(out, Count{Bucket: b, Term: term, Count: count})
+		}
+	}
+	if len(out) == 0 {
+		return nil
+	}
+	if err := save(out); err != nil {
+		a.mu.Lock()
+		defer a.mu.Unlock()
+		for _, c := range out {
+			addCount(a.pending, c.Bucket, c.Term, c.Count)
+		}
+		return err
+	}
+	return nil
+}
+
+func addCount(counts map[time.Time]map[Term]int, b time.Time, term Term, n int) {
+	if counts[b] == nil {
+		counts[b] = map[Term]int{}
+	}
+	counts[b][term] += n
+}
+
+// Prune drops buckets too old to be part of any baseline and returns the
+// cutoff so saved counts can be pruned to match.
+func (a *Aggregator) Prune(now time.Time) time.Time {
+	cutoff := now.UTC().Add(-a.retain).Truncate(a.bucket)
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	for b := range a.counts {
+		if b.Before(cutoff) {
+			delete(a.counts, b)
+			delete(a.pending, b)
+		}
+	}
+	return cutoff
+}
+
+// Top ranks the terms of one kind used at least minCount times in the window
+// ending at now. Terms are scored by how far their count beats what the
+// baseline period before the window predicts, so a term that's always busy
+// doesn't trend just for being common.
+func (a *Aggregator) Top(now time.Time, kind Kind, window, baseline time.Duration, minCount, limit int) []Trend {
+	windowStart := now.UTC().Add(-window)
+	baselineStart := windowStart.Add(-baseline)
+	current := map[Term]int{}
+	before := map[Term]int{}
+	a.mu.Lock()
+	for b, terms := range a.counts {
+		if b.Before(baselineStart) || b.After(now) {
+			continue
+		}
+		for term, count := range terms {
+			if term.Kind != kind {
+				continue
+			}
+			if b.Before(windowStart) {
+				before[term] += count
+			} else {
+				current[term] += count
+			}
+		}
+	}
+	a.mu.Unlock()
+	out := []Trend{}
+	scale := window.Hours() / baseline.Hours()
+	for term, count := range current {
+		if count < minCount {
+			continue
+		}
+		expected := float64(before[term]) * scale
+		if float64(count) <= expected {
+			continue
+		}
+		out = append(out, Trend{Term: term, Count: count, Expected: expected, Score: growthScore(count, expected)})
+	}
+	sort.Slice(out, func(i, j int) bool {
+		if out[i].Score != out[j].Score {
+			return out[i].Score > out[j].Score
+		}
+		if out[i].Count != out[j].Count {
+			return out[i].Count > out[j].Count
+		}
+		return out[i].Term.Text < out[j].Term.Text
+	})
+	if len(out) > limit {
+		out = out[:limit]
+	}
+	return out
+}
+
+// growthScore measures how far count beats expected in standard deviations,
+// treating usage as a Poisson process. Adding one to the expected count keeps
+// brand new terms from scoring infinitely high.
+func growthScore(count int, expected float64) float64 {
+	return (float64(count) - expected) / math.Sqrt(expected+1)
+}
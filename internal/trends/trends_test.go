@@ -0,0 +1,135 @@
+package trends
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func addN(a *Aggregator, at time.Time, term Term, n int) {
+	for range n {
+		a.Add(at, []Term{term})
+	}
+}
+
+func TestTopRanksGrowthOverVolume(t *testing.T) {
+	now := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+	a := NewAggregator(5*time.Minute, 48*time.Hour)
+	steady := Term{Kind: KindHashtag, Text: "news"}
+	rising := Term{Kind: KindHashtag, Text: "eclipse"}
+	// news sees about 40 uses an hour all day, eclipse only starts now.
+	for h := 1; h <= 24; h++ {
+		addN(a, now.Add(-time.Duration(h)*time.Hour-time.Minute), steady, 40)
+	}
+	addN(a, now.Add(-10*time.Minute), steady, 45)
+	addN(a, now.Add(-10*time.Minute), rising, 12)
+
+	top := a.Top(now, KindHashtag, time.Hour, 24*time.Hour, 3, 10)
+	if len(top) != 2 || top[0].Term != rising || top[1].Term != steady {
+		t.Fatalf("Unexpected ranking: %+v", top)
+	}
+	if top[1].Expected != 40 {
+		t.Errorf("Expected baseline of 40 for news, got %v", top[1].Expected)
+	}
+}
+
+func TestTopSkipsShrinkingAndRareTerms(t *testing.T) {
+	now := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+	a := NewAggregator(5*time.Minute, 48*time.Hour)
+	fading := Term{Kind: KindPhrase, Text: "old news"}
+	rare := Term{Kind: KindPhrase, Text: "one off"}
+	addN(a, now.Add(-5*time.Hour), fading, 100)
+	addN(a, now.Add(-time.Minute), fading, 1)
+	addN(a, now.Add(-time.Minute), rare, 2)
+
+	if top := a.Top(now, KindPhrase, time.Hour, 24*time.Hour, 3, 10); len(top) != 0 {
+		t.Errorf("Expected no trends, got: %+v", top)
+	}
+}
+
+func TestFlushKeepsUsesOnError(t *testing.T) {
+	now := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+	a := NewAggregator(5*time.Minute, 48*time.Hour)
+	a.Add(now, []Term{{Kind: KindHashtag, Text: "go"}})
+
+	if err := a.Flush(func([]Count) error { return errors.New("down") }); err == nil {
+		t.Fatal("Expected the save error to be returned")
+	}
+	a.Add(now, []Term{{Kind: KindHashtag, Text: "go"}})
+	var saved []Count
+	if err := a.Flush(func(c []Count) error { saved = c; return nil }); err != nil {
+		t.Fatal(err)
+	}
+	if len(saved) != 1 || saved[0].Count != 2 || !saved[0].Bucket.Equal(now) {
+		t.Errorf("Unexpected counts: %+v", saved)
+	}
+	saved = nil
+	a.Flush(func(c []Count) error { saved = c; return nil })
+	if saved != nil {
+		t.Errorf("Nothing should be left to flush, got: %+v", saved)
+	}
+}
+
+func TestInstancesShareTotals(t *testing.T) {
+	now := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+	term := Term{Kind: KindHashtag, Text: "go"}
+	// stored stands in for trend_counts, which adds each flushed delta.
+	stored := map[Term]int{}
+	save := func(counts []Count) error {
+		for _, c := range counts {
+			stored[c.Term] += c.Count
+		}
+		return nil
+	}
+	load := func(a *Aggregator) {
+		totals := []Count{}
+		for term, count := range stored {
+			totals = append(totals, Count{Bucket: now, Term: term, Count: count})
+		}
+		a.Load(totals)
+	}
+	a := NewAggregator(5*time.Minute, 48*time.Hour)
+	b := NewAggregator(5*time.Minute, 48*time.Hour)
+	addN(a, now, term, 3)
+	addN(b, now, term, 2)
+	a.Flush(save)
+	b.Flush(save)
+	a.Flush(save)
+	addN(a, now, term, 1)
+	load(a)
+	load(b)
+
+	if stored[term] != 5 {
+		t.Errorf("Expected 5 stored uses, got %d", stored[term])
+	}
+	if top := a.Top(now.Add(time.Minute), KindHashtag, time.Hour, 24*time.Hour, 1, 10); len(top) != 1 || top[0].Count != 6 {
+		t.Errorf("Expected the shared total plus the unflushed use, got: %+v", top)
+	}
+	if top := b.Top(now.Add(time.Minute), KindHashtag, time.Hour, 24*time.Hour, 1, 10); len(top) != 1 || top[0].Count != 5 {
+		t.Errorf("Expected the shared total, got: %+v", top)
+	}
+}
+
+func TestMergeReplacesOnlyChangedCounts(t *testing.T) {
+	now := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+	golang := Term{Kind: KindHashtag, Text: "go"}
+	rust := Term{Kind: KindHashtag, Text: "rust"}
+	a := NewAggregator(5*time.Minute, 48*time.Hour)
+	a.Load([]Count{{Bucket: now, Term: golang, Count: 4}, {Bucket: now, Term: rust, Count: 3}})
+	addN(a, now, golang, 1)
+
+	a.Merge([]Count{{Bucket: now, Term: golang, Count: 10}})
+	a.Merge([]Count{{Bucket: now, Term: golang, Count: 10}})
+
+	top := a.Top(now.Add(time.Minute), KindHashtag, time.Hour, 24*time.Hour, 1, 10)
+	got := map[string]int{}
+	for _, trend := range top {
+		got[trend.Term.Text] = trend.Count
+	}
+	if got["go"] != 11 {
+		t.Errorf("Expected the new total plus the unflushed use, got %d", got["go"])
+	}
+	if got["rust"] != 3 {
+		t.Errorf("Counts left out of the merge should stay, got %d", got["rust"])
+	}
+}
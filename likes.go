@@ -0,0 +1,77 @@
+package main
+
+import (
+	"context"
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/cameronbarnes/go_chirpy/internal/database"
+	"github.com/google/uuid"
+)
+
+func (c *apiConfig) addLike(w http.ResponseWriter, r *http.Request, user database.GetUserRow) {
+	id, err := uuid.Parse(r.PathValue("chirpID"))
+	if err != nil {
+		respondWithError(w, 400, "UUID provided is not valid")
+		return
+	}
+	chirp, err := c.getOriginalChirp(id, &user)
+	if err != nil {
+		respondWithError(w, 404, "Chirp Not Found")
+		return
+	}
+	err = c.db.AddLike(context.Background(), database.AddLikeParams{UserID: user.ID, ChirpID: chirp.ID})
+	if err != nil {
+		log.Printf("Failed to like chirp with err: %s", err)
+		respondWithError(w, 500, "Failed to like Chirp")
+		return
+	}
+	w.WriteHeader(204)
+}
+
+func (c *apiConfig) deleteLike(w http.ResponseWriter, r *http.Request, user database.GetUserRow) {
+	id, err := uuid.Parse(r.PathValue("chirpID"))
+	if err != nil {
+		respondWithError(w, 400, "UUID provided is not valid")
+		return
+	}
+	err = c.db.DeleteLike(context.Background(), database.DeleteLikeParams{UserID: user.ID, ChirpID: id})
+	if err != nil {
+		log.Printf("Failed to unlike chirp with err: %s", err)
+		respondWithError(w, 500, "Failed to unlike Chirp")
+		return
+	}
+	w.WriteHeader(204)
+}
+
+func (c *apiConfig) getChirpLikes(w http.ResponseWriter, r *http.Request, viewer *database.GetUserRow) {
+	id, err := uuid.Parse(r.PathValue("chirpID"))
+	if err != nil {
+		respondWithError(w, 400, "UUID provided is not valid")
+		return
+	}
+	chirp, err := c.getOriginalChirp(id, viewer)
+	if err != nil {
+		respondWithError(w, 404, "Chirp Not Found")
+		return
+	}
+	p, err := parsePage(r)
+	if err != nil {
+		respondWithError(w, 400, err.Error())
+		return
+	}
+	likers, err := c.db.ListChirpLikers(context.Background(), database.ListChirpLikersParams{ChirpID: chirp.ID, AfterCreatedAt: p.AfterCreatedAt, AfterID: p.AfterID, PageSize: p.fetchLimit()})
+	if err != nil {
+		log.Printf("Failed to get likes with err: %s", err)
+		respondWithError(w, 500, "Failed to get likes")
+		return
+	}
+	likers = paginate(w, r, p, likers, func(liker database.ListChirpLikersRow) (time.Time, uuid.UUID) {
+		return liker.LikedAt, liker.ID
+	})
+	if likers == nil {
+		likers = []database.ListChirpLikersRow{}
+	}
+	respondWithJSON(w, 200, likers)
+}
@@ -9,23 +9,49 @@ import (
 	"log"
 	"net/http"
 	"os"
-	"sort"
+	"path/filepath"
+	"strconv"
 	"strings"
 	"sync/atomic"
 	"time"
 
 	"github.com/cameronbarnes/go_chirpy/internal/auth"
+	"github.com/cameronbarnes/go_chirpy/internal/chirptext"
 	"github.com/cameronbarnes/go_chirpy/internal/database"
+	"github.com/cameronbarnes/go_chirpy/internal/storage"
+	"github.com/cameronbarnes/go_chirpy/internal/trends"
 	"github.com/google/uuid"
 	"github.com/joho/godotenv"
-	_ "github.com/lib/pq"
+	"github.com/lib/pq"
 )
 
+// staticDir holds the files served under /app/. Nothing else belongs in it.
+const staticDir = "./static"
+
 type apiConfig struct {
 	fileserverHits atomic.Int32
 	db             *database.Queries
+	dbConn         *sql.DB
 	jwtSecret      string
 	polkaKey       string
+	adminKey       string
+	editWindow     time.Duration
+	restoreWindow  time.Duration
+	processors     chirpPipeline
+	media          storage.Store
+	trends         *trends.Aggregator
+	// trendsLoadedAt is when the newest trend count loaded was saved, and
+	// trendsTop the trends last ranked from them.
+	trendsLoadedAt time.Time
+	trendsTop      atomic.Pointer[trendsOut]
+	exports        storage.Store
+	// exportSecret signs archive download links. exportURLTTL is how long
+	// archives are kept, and so how long their links work.
+	exportSecret string
+	exportURLTTL time.Duration
+	// reportHideThreshold is how many open reports hide a chirp until a
+	// moderator gets to it.
+	reportHideThreshold int
 }
 
 func (c *apiConfig) middlewareMetricsInc(next http.Handler) http.Handler {
@@ -35,6 +61,18 @@ func (c *apiConfig) middlewareMetricsInc(next http.Handler) http.Handler {
 	})
 }
 
+func (c *apiConfig) withTx(fn func(q *database.Queries) error) error {
+	tx, err := c.dbConn.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+	if err := fn(c.db.WithTx(tx)); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
 func (c *apiConfig) hitsMetricsHandler(w http.ResponseWriter, _ *http.Request) {
 	w.Header().Set("Content-Type", "text/html; charset=utf-8")
 	w.WriteHeader(200)
@@ -58,6 +96,7 @@ func (c *apiConfig) addUser(w http.ResponseWriter, r *http.Request) {
 	type req struct {
 		Email    string `json:"email"`
 		Password string `json:"password"`
+		Handle   string `json:"handle"`
 	}
 	arg, err := handleParse[req](w, r)
 	if err != nil {
@@ -68,7 +107,20 @@ func (c *apiConfig) addUser(w http.ResponseWriter, r *http.Request) {
 		respondWithError(w, 400, "Password is not valid")
 		return
 	}
-	user, err := c.db.CreateUser(context.Background(), database.CreateUserParams{Email: strings.ToLower(arg.Email), HashedPassword: hash})
+	var handle *string
+	if arg.Handle != "" {
+		normalized := chirptext.NormalizeHandle(arg.Handle)
+		if !chirptext.ValidHandle(normalized) {
+			respondWithError(w, 400, "Handle is not valid")
+			return
+		}
+		handle = &normalized
+	}
+	user, err := c.db.CreateUser(context.Background(), database.CreateUserParams{Email: strings.ToLower(arg.Email), HashedPassword: hash, Handle: handle})
+	if isUniqueViolation(err) {
+		respondWithError(w, 409, "Email or handle is already in use")
+		return
+	}
 	if err != nil {
 		log.Printf("Failed to create user with error: %s", err.Error())
 		respondWithError(w, 500, "Failed to create user")
@@ -119,48 +171,172 @@ func (c *apiConfig) login(w http.ResponseWriter, r *http.Request) {
 
 func (c *apiConfig) addChirp(w http.ResponseWriter, r *http.Request, user database.GetUserRow) {
 	type chirpArgs struct {
-		Body string `json:"body"`
+		Body           string      `json:"body"`
+		InReplyTo      *uuid.UUID  `json:"in_reply_to"`
+		QuoteOf        *uuid.UUID  `json:"quote_of"`
+		PublishAt      *time.Time  `json:"publish_at"`
+		MediaIDs       []uuid.UUID `json:"media_ids"`
+		Poll           *pollArgs   `json:"poll"`
+		Visibility     string      `json:"visibility"`
+		ContentWarning string      `json:"content_warning"`
+		Sensitive      bool        `json:"sensitive"`
 	}
 	arg, err := handleParse[chirpArgs](w, r)
 	if err != nil {
 		respondWithError(w, 400, "Invalid Request")
 		return
 	}
-	body, err := validateChirp(arg.Body)
-	if err != nil {
+	if err := validateMediaIDs(arg.MediaIDs); err != nil {
+		respondWithError(w, 400, err.Error())
+		return
+	}
+	if arg.Visibility == "" {
+		arg.Visibility = visibilityPublic
+	}
+	if !validVisibility(arg.Visibility) {
+		respondWithError(w, 400, "Visibility must be public, followers, mentioned or private")
+		return
+	}
+	arg.ContentWarning = strings.TrimSpace(arg.ContentWarning)
+	if chirptext.Length(arg.ContentWarning) > maxContentWarningLength {
+		respondWithError(w, 400, fmt.Sprintf("Content warning must be at most %d characters", maxContentWarningLength))
+		return
+	}
+	params := database.AddChirpParams{UserID: user.ID, Visibility: arg.Visibility, ContentWarning: arg.ContentWarning, Sensitive: arg.Sensitive}
+	if arg.InReplyTo != nil {
+		parent, err := c.db.GetChirp(context.Background(), *arg.InReplyTo)
+		visible := false
+		if err == nil && !chirpRemoved(parent) {
+			visible, err = c.chirpVisibleTo(parent, &user)
+		}
+		if err != nil && !errors.Is(err, sql.ErrNoRows) {
+			log.Printf("Failed to get parent chirp with err: %s", err)
+			respondWithError(w, 500, "Failed to create Chirp")
+			return
+		}
+		if !visible {
+			respondWithError(w, 404, "Chirp being replied to was not found")
+			return
+		}
+		params.ParentID = uuid.NullUUID{UUID: parent.ID, Valid: true}
+	}
+	if arg.QuoteOf != nil {
+		quoted, err := c.getOriginalChirp(*arg.QuoteOf, &user)
+		if err != nil {
+			respondWithError(w, 404, "Chirp being quoted was not found")
+			return
+		}
+		if !chirpPublic(quoted) {
+			respondWithError(w, 400, "Only public Chirps can be quoted")
+			return
+		}
+		params.QuoteOf = uuid.NullUUID{UUID: quoted.ID, Valid: true}
+	}
+	if arg.PublishAt != nil {
+		if !arg.PublishAt.After(time.Now()) {
+			respondWithError(w, 400, "publish_at must be in the future")
+			return
+		}
+		params.PublishAt = sql.NullTime{Time: arg.PublishAt.UTC(), Valid: true}
+	}
+	pending := pendingChirp{Author: user, Body: arg.Body}
+	if arg.Poll != nil {
+		publishAt := time.Now().UTC()
+		if params.PublishAt.Valid {
+			publishAt = params.PublishAt.Time
+		}
+		pending.PollOptions, err = validatePoll(*arg.Poll, publishAt)
+		if err != nil {
+			respondWithError(w, 400, err.Error())
+			return
+		}
+	}
+	if err := c.processors.Run(context.Background(), &pending); err != nil {
+		respondWithChirpError(w, err)
+		return
+	}
+	params.Body = pending.Body
+	params.Flagged = pending.Flagged
+	params.Entities = chirpEntities(pending.Body)
+	var chirp database.Chirp
+	err = c.withTx(func(q *database.Queries) error {
+		chirp, err = q.AddChirp(context.Background(), params)
+		if err != nil {
+			return err
+		}
+		if err := attachMedia(q, chirp, arg.MediaIDs); err != nil {
+			return err
+		}
+		if arg.Poll != nil {
+			if err := savePoll(q, chirp, arg.Poll.ClosesAt.UTC(), pending.PollOptions); err != nil {
+				return err
+			}
+		}
+		return saveChirpEntities(q, chirp)
+	})
+	if errors.Is(err, errMediaNotFound) {
 		respondWithError(w, 400, err.Error())
 		return
 	}
-	chirp, err := c.db.AddChirp(context.Background(), database.AddChirpParams{Body: body, UserID: user.ID})
 	if err != nil {
 		respondWithError(w, 500, err.Error())
 		return
 	}
-	respondWithJSON(w, 201, chirp)
+	c.recordTrends(chirp)
+	c.respondWithChirp(w, 201, chirp, &user)
 }
 
-func (c *apiConfig) deleteChirp(w http.ResponseWriter, r *http.Request, user database.GetUserRow) {
+// getOwnedChirp loads the chirp named in the path and checks that user wrote
+// it. On failure it has already responded and returns false.
+func (c *apiConfig) getOwnedChirp(w http.ResponseWriter, r *http.Request, user database.GetUserRow) (database.Chirp, bool) {
 	id := r.PathValue("chirpID")
 	if id == "" {
 		respondWithError(w, 404, "Chirp Not Found")
-		return
+		return database.Chirp{}, false
 	}
 	uuid, err := uuid.Parse(id)
 	if err != nil {
 		respondWithError(w, 400, "UUID provided is not valid")
-		return
+		return database.Chirp{}, false
 	}
 	chirp, err := c.db.GetChirp(context.Background(), uuid)
 	if err != nil {
 		respondWithError(w, 404, "Chirp Not Found")
-		return
+		return database.Chirp{}, false
+	}
+	if chirpRemoved(chirp) {
+		respondWithError(w, 410, "Chirp has been deleted")
+		return database.Chirp{}, false
 	}
 	if chirp.UserID != user.ID {
 		w.WriteHeader(403)
+		return database.Chirp{}, false
+	}
+	return chirp, true
+}
+
+func (c *apiConfig) deleteChirp(w http.ResponseWriter, r *http.Request, user database.GetUserRow) {
+	chirp, ok := c.getOwnedChirp(w, r, user)
+	if !ok {
 		return
 	}
-	_, err2 := c.db.DeleteChirp(context.Background(), database.DeleteChirpParams{ID: uuid, UserID: user.ID})
-	if err2 != nil {
+	if chirp.RechirpOf.Valid {
+		// Rechirps have nothing worth restoring, so undo them outright.
+		_, err := c.db.DeleteRechirp(context.Background(), database.DeleteRechirpParams{UserID: user.ID, RechirpOf: chirp.RechirpOf})
+		if err != nil {
+			respondWithError(w, 500, "Failed to delete Chirp")
+			return
+		}
+		w.WriteHeader(204)
+		return
+	}
+	err := c.withTx(func(q *database.Queries) error {
+		if _, err := q.DeleteChirp(context.Background(), database.DeleteChirpParams{ID: chirp.ID, UserID: user.ID}); err != nil {
+			return err
+		}
+		return q.DeletePinsForChirp(context.Background(), chirp.ID)
+	})
+	if err != nil {
 		respondWithError(w, 500, "Failed to delete Chirp")
 		return
 	}
@@ -171,6 +347,7 @@ func (c *apiConfig) updateUser(w http.ResponseWriter, r *http.Request, user data
 	type updateArgs struct {
 		Email    string `json:"email"`
 		Password string `json:"password"`
+		Handle   string `json:"handle"`
 	}
 	args, err := handleParse[updateArgs](w, r)
 	if err != nil {
@@ -182,42 +359,97 @@ func (c *apiConfig) updateUser(w http.ResponseWriter, r *http.Request, user data
 		respondWithError(w, 400, "Password is not valid")
 		return
 	}
-	updated, err := c.db.UpdateUser(context.Background(), database.UpdateUserParams{ID: user.ID, Email: args.Email, HashedPassword: hashed})
+	handle := sql.NullString{}
+	if args.Handle != "" {
+		handle = sql.NullString{String: chirptext.NormalizeHandle(args.Handle), Valid: true}
+		if !chirptext.ValidHandle(handle.String) {
+			respondWithError(w, 400, "Handle is not valid")
+			return
+		}
+	}
+	updated, err := c.db.UpdateUser(context.Background(), database.UpdateUserParams{ID: user.ID, Email: args.Email, HashedPassword: hashed, Handle: handle})
+	if isUniqueViolation(err) {
+		respondWithError(w, 409, "Email or handle is already in use")
+		return
+	}
 	respondWithJSON(w, 200, updated)
 }
 
-func (c *apiConfig) getChirps(w http.ResponseWriter, r *http.Request) {
-	var chirps_out []database.Chirp
-	author_id := r.URL.Query().Get("author_id")
-	if author_id == "" {
-		chirps, err := c.db.AllChirps(context.Background())
-		if err != nil {
-			log.Printf("Failed to get chirps with err: %s", err)
-			respondWithError(w, 500, "Failed to get chirps")
-			return
-		}
-		chirps_out = chirps
-	} else {
+func (c *apiConfig) setPreferences(w http.ResponseWriter, r *http.Request, user database.GetUserRow) {
+	type preferenceArgs struct {
+		SensitiveContent string `json:"sensitive_content"`
+	}
+	arg, err := handleParse[preferenceArgs](w, r)
+	if err != nil {
+		return
+	}
+	if !validSensitiveContent(arg.SensitiveContent) {
+		respondWithError(w, 400, "Sensitive content must be hide, blur or show")
+		return
+	}
+	err = c.db.SetSensitiveContent(context.Background(), database.SetSensitiveContentParams{ID: user.ID, SensitiveContent: arg.SensitiveContent})
+	if err != nil {
+		log.Printf("Failed to set preferences with err: %s", err)
+		respondWithError(w, 500, "Failed to set preferences")
+		return
+	}
+	respondWithJSON(w, 200, arg)
+}
+
+func (c *apiConfig) getChirps(w http.ResponseWriter, r *http.Request, viewer *database.GetUserRow) {
+	p, err := parsePage(r)
+	if err != nil {
+		respondWithError(w, 400, err.Error())
+		return
+	}
+	since, until, err := parseTimeRange(r)
+	if err != nil {
+		respondWithError(w, 400, err.Error())
+		return
+	}
+	params := database.ListChirpsParams{Since: since, Until: until, AfterCreatedAt: p.AfterCreatedAt, AfterID: p.AfterID, PageSize: p.fetchLimit()}
+	if author_id := r.URL.Query().Get("author_id"); author_id != "" {
 		id, err := uuid.Parse(author_id)
 		if err != nil {
 			respondWithError(w, 400, "Failed to parse UserID UUID")
 			return
 		}
-		chirps, err := c.db.AllChirpsFromUser(context.Background(), id)
-		if err != nil {
-			log.Printf("Failed to get chirps with err: %s", err)
-			respondWithError(w, 500, "Failed to get chirps")
-			return
-		}
-		chirps_out = chirps
+		params.AuthorID = uuid.NullUUID{UUID: id, Valid: true}
 	}
-	if r.URL.Query().Get("sort") == "desc" {
-		sort.Slice(chirps_out, func(i, j int) bool { return chirps_out[i].CreatedAt.After(chirps_out[j].CreatedAt) })
+	params.ViewerID = viewerID(viewer)
+	var chirps []database.Chirp
+	switch r.URL.Query().Get("sort") {
+	case "", "asc":
+		chirps, err = c.db.ListChirps(context.Background(), params)
+	case "desc":
+		chirps, err = c.db.ListChirpsDesc(context.Background(), database.ListChirpsDescParams(params))
+	default:
+		respondWithError(w, 400, "sort must be asc or desc")
+		return
+	}
+	if err != nil {
+		log.Printf("Failed to get chirps with err: %s", err)
+		respondWithError(w, 500, "Failed to get chirps")
+		return
+	}
+	chirps = pageChirps(w, r, p, chirps)
+	// An author's pinned chirps lead the first page of their unfiltered
+	// listing, and are left out of the rest of that page. Later pages still
+	// show them in their place.
+	if !params.AuthorID.Valid || p.AfterID.Valid || since.Valid || until.Valid {
+		c.respondWithChirps(w, 200, chirps, viewer)
+		return
 	}
-	respondWithJSON(w, 200, chirps_out)
+	pinned, err := c.db.ListPinnedChirps(context.Background(), database.ListPinnedChirpsParams{UserID: params.AuthorID.UUID, ViewerID: params.ViewerID})
+	if err != nil {
+		log.Printf("Failed to get pinned chirps with err: %s", err)
+		respondWithError(w, 500, "Failed to get chirps")
+		return
+	}
+	c.respondWithPinnedChirps(w, 200, pinned, chirps, viewer)
 }
 
-func (c *apiConfig) getChirp(w http.ResponseWriter, r *http.Request) {
+func (c *apiConfig) getChirp(w http.ResponseWriter, r *http.Request, viewer *database.GetUserRow) {
 	id := r.PathValue("chirpID")
 	if id == "" {
 		respondWithError(w, 404, "Chirp Not Found")
@@ -238,7 +470,21 @@ func (c *apiConfig) getChirp(w http.ResponseWriter, r *http.Request) {
 		}
 		return
 	}
-	respondWithJSON(w, 200, chirp)
+	visible, err := c.chirpVisibleTo(chirp, viewer)
+	if err != nil {
+		log.Printf("Failed to check chirp visibility with err: %s", err)
+		respondWithError(w, 500, "Failed to get Chirp")
+		return
+	}
+	if !visible {
+		respondWithError(w, 404, "Chirp Not Found")
+		return
+	}
+	if chirpRemoved(chirp) {
+		respondWithError(w, 410, "Chirp has been deleted")
+		return
+	}
+	c.respondWithChirp(w, 200, chirp, viewer)
 }
 
 func userOutFromLogin(w http.ResponseWriter, code int, obj database.User, jwt, refresh string) {
@@ -247,11 +493,12 @@ func userOutFromLogin(w http.ResponseWriter, code int, obj database.User, jwt, r
 		CreatedAt    time.Time `json:"created_at"`
 		UpdatedAt    time.Time `json:"updated_at"`
 		Email        string    `json:"email"`
+		Handle       *string   `json:"handle"`
 		Token        string    `json:"token"`
 		RefreshToken string    `json:"refresh_token"`
 		IsChirpyRed  bool      `json:"is_chirpy_red"`
 	}
-	respondWithJSON(w, code, user_out{ID: obj.ID, CreatedAt: obj.CreatedAt, UpdatedAt: obj.UpdatedAt, Email: obj.Email, Token: jwt, RefreshToken: refresh, IsChirpyRed: obj.IsChirpyRed})
+	respondWithJSON(w, code, user_out{ID: obj.ID, CreatedAt: obj.CreatedAt, UpdatedAt: obj.UpdatedAt, Email: obj.Email, Handle: obj.Handle, Token: jwt, RefreshToken: refresh, IsChirpyRed: obj.IsChirpyRed})
 }
 
 func (c *apiConfig) refresh(w http.ResponseWriter, r *http.Request) {
@@ -331,6 +578,16 @@ func healthcheck(w http.ResponseWriter, _ *http.Request) {
 	w.Write([]byte("OK"))
 }
 
+func isUniqueViolation(err error) bool {
+	var pqErr *pq.Error
+	return errors.As(err, &pqErr) && pqErr.Code == "23505"
+}
+
+func isForeignKeyViolation(err error) bool {
+	var pqErr *pq.Error
+	return errors.As(err, &pqErr) && pqErr.Code == "23503"
+}
+
 func handleParse[T any](w http.ResponseWriter, r *http.Request) (T, error) {
 	decoder := json.NewDecoder(r.Body)
 	var val T
@@ -365,24 +622,66 @@ func respondWithJSON[T any](w http.ResponseWriter, code int, payload T) {
 	w.Write(dat)
 }
 
-func cleanStr(input string, bad string) string {
-	out := []string{}
-	for str := range strings.SplitSeq(input, " ") {
-		if strings.ToLower(str) == bad {
-			out = append(out, "****")
-		} else {
-			out = append(out, str)
-		}
+func durationFromEnv(name string, fallback time.Duration) time.Duration {
+	val := os.Getenv(name)
+	if val == "" {
+		return fallback
+	}
+	out, err := time.ParseDuration(val)
+	if err != nil {
+		fmt.Printf("%s is not a valid duration: %s\n", name, err)
+		os.Exit(1)
 	}
-	return strings.Join(out, " ")
+	return out
 }
 
-func validateChirp(text string) (string, error) {
-	if len(text) > 140 {
-		return "", errors.New("Chirp is too long")
+func intFromEnv(name string, fallback int) int {
+	val := os.Getenv(name)
+	if val == "" {
+		return fallback
+	}
+	out, err := strconv.Atoi(val)
+	if err != nil || out < 1 {
+		fmt.Printf("%s must be a positive integer\n", name)
+		os.Exit(1)
 	}
+	return out
+}
 
-	return cleanStr(cleanStr(cleanStr(text, "kerfuffle"), "sharbert"), "fornax"), nil
+// dirFromEnv reads a storage directory, refusing any inside staticDir since
+// everything there is served to anyone under /app/.
+func dirFromEnv(name, fallback string) string {
+	dir := os.Getenv(name)
+	if dir == "" {
+		dir = fallback
+	}
+	inside, err := dirInside(dir, staticDir)
+	if err != nil {
+		fmt.Printf("%s is not a valid directory: %s\n", name, err)
+		os.Exit(1)
+	}
+	if inside {
+		fmt.Printf("%s must be outside %s, which is served publicly\n", name, staticDir)
+		os.Exit(1)
+	}
+	return dir
+}
+
+// dirInside reports whether dir is root or somewhere below it.
+func dirInside(dir, root string) (bool, error) {
+	dir, err := filepath.Abs(dir)
+	if err != nil {
+		return false, err
+	}
+	root, err = filepath.Abs(root)
+	if err != nil {
+		return false, err
+	}
+	rel, err := filepath.Rel(root, dir)
+	if err != nil {
+		return false, err
+	}
+	return rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator)), nil
 }
 
 func main() {
@@ -390,27 +689,106 @@ func main() {
 	dbUrl := os.Getenv("DB_URL")
 	jwtSecret := os.Getenv("JWT_SECRET")
 	polkaKey := os.Getenv("POLKA_KEY")
+	adminKey := os.Getenv("ADMIN_KEY")
+	editWindow := durationFromEnv("CHIRP_EDIT_WINDOW", 15*time.Minute)
+	restoreWindow := durationFromEnv("CHIRP_RESTORE_WINDOW", 30*24*time.Hour)
+	mediaStore, err := storage.NewLocalStore(dirFromEnv("MEDIA_DIR", "./media"))
+	if err != nil {
+		fmt.Println(err)
+		os.Exit(1)
+	}
+	exportStore, err := storage.NewLocalStore(dirFromEnv("EXPORT_DIR", "./exports"))
+	if err != nil {
+		fmt.Println(err)
+		os.Exit(1)
+	}
+	// Download links get their own key, so leaking one can't forge the other.
+	exportSecret := os.Getenv("EXPORT_SIGNING_KEY")
+	if exportSecret == "" || exportSecret == jwtSecret {
+		fmt.Println("EXPORT_SIGNING_KEY must be set, and differ from JWT_SECRET")
+		os.Exit(1)
+	}
 	db, err := sql.Open("postgres", dbUrl)
 	if err != nil {
 		fmt.Println(err)
 		os.Exit(1)
 	}
-	cfg := apiConfig{db: database.New(db), jwtSecret: jwtSecret, polkaKey: polkaKey}
+	cfg := apiConfig{db: database.New(db), dbConn: db, jwtSecret: jwtSecret, polkaKey: polkaKey, adminKey: adminKey, editWindow: editWindow, restoreWindow: restoreWindow, media: mediaStore}
+	cfg.reportHideThreshold = intFromEnv("REPORT_HIDE_THRESHOLD", 5)
+	cfg.trends = trends.NewAggregator(trendBucket, trendRetain)
+	cfg.exports, cfg.exportSecret = exportStore, exportSecret
+	cfg.exportURLTTL = durationFromEnv("EXPORT_URL_TTL", 24*time.Hour)
+	if err := cfg.loadTrends(); err != nil {
+		log.Printf("Failed to load trends with err: %s", err)
+	}
+	cfg.rankTrends()
+	cfg.processors, err = chirpPipelineFromEnv(cfg.db)
+	if err != nil {
+		fmt.Println(err)
+		os.Exit(1)
+	}
 	mux := http.NewServeMux()
-	mux.Handle("/app/", http.StripPrefix("/app", cfg.middlewareMetricsInc(http.FileServer(http.Dir("./")))))
-	mux.HandleFunc("POST /api/chirps", cfg.getUserMiddleware(cfg.addChirp))
-	mux.HandleFunc("GET /api/chirps", cfg.getChirps)
-	mux.HandleFunc("GET /api/chirps/{chirpID}", cfg.getChirp)
+	mux.Handle("/app/", http.StripPrefix("/app", cfg.middlewareMetricsInc(http.FileServer(http.Dir(staticDir)))))
+	mux.HandleFunc("POST /api/chirps", cfg.getUserMiddleware(cfg.requireActiveUser(cfg.addChirp)))
+	mux.HandleFunc("GET /api/chirps", cfg.getOptionalUserMiddleware(cfg.getChirps))
+	mux.HandleFunc("GET /api/chirps/search", cfg.getOptionalUserMiddleware(cfg.searchChirps))
+	mux.HandleFunc("GET /api/chirps/{chirpID}", cfg.getOptionalUserMiddleware(cfg.getChirp))
+	mux.HandleFunc("GET /api/chirps/{chirpID}/thread", cfg.getOptionalUserMiddleware(cfg.getThread))
+	mux.HandleFunc("POST /api/chirps/{chirpID}/rechirps", cfg.getUserMiddleware(cfg.requireActiveUser(cfg.addRechirp)))
+	mux.HandleFunc("DELETE /api/chirps/{chirpID}/rechirps", cfg.getUserMiddleware(cfg.deleteRechirp))
+	mux.HandleFunc("GET /api/chirps/{chirpID}/likes", cfg.getOptionalUserMiddleware(cfg.getChirpLikes))
+	mux.HandleFunc("POST /api/chirps/{chirpID}/likes", cfg.getUserMiddleware(cfg.requireActiveUser(cfg.addLike)))
+	mux.HandleFunc("DELETE /api/chirps/{chirpID}/likes", cfg.getUserMiddleware(cfg.deleteLike))
+	mux.HandleFunc("POST /api/chirps/{chirpID}/poll/votes", cfg.getUserMiddleware(cfg.requireActiveUser(cfg.addPollVote)))
+	mux.HandleFunc("POST /api/chirps/{chirpID}/reports", cfg.getUserMiddleware(cfg.requireActiveUser(cfg.addReport)))
+	mux.HandleFunc("POST /api/chirps/{chirpID}/appeal", cfg.getUserMiddleware(cfg.addAppeal))
+	mux.HandleFunc("PUT /api/chirps/{chirpID}", cfg.getUserMiddleware(cfg.requireActiveUser(cfg.editChirp)))
+	mux.HandleFunc("GET /api/chirps/{chirpID}/revisions", cfg.getOptionalUserMiddleware(cfg.getChirpRevisions))
+	mux.HandleFunc("POST /api/chirps/{chirpID}/restore", cfg.getUserMiddleware(cfg.requireActiveUser(cfg.restoreChirp)))
 	mux.HandleFunc("DELETE /api/chirps/{chirpID}", cfg.getUserMiddleware(cfg.deleteChirp))
+	mux.HandleFunc("GET /api/drafts", cfg.getUserMiddleware(cfg.getDrafts))
+	mux.HandleFunc("POST /api/drafts", cfg.getUserMiddleware(cfg.addDraft))
+	mux.HandleFunc("GET /api/drafts/{id}", cfg.getUserMiddleware(cfg.getDraft))
+	mux.HandleFunc("PUT /api/drafts/{id}", cfg.getUserMiddleware(cfg.updateDraft))
+	mux.HandleFunc("DELETE /api/drafts/{id}", cfg.getUserMiddleware(cfg.deleteDraft))
+	mux.HandleFunc("POST /api/drafts/{id}/publish", cfg.getUserMiddleware(cfg.requireActiveUser(cfg.publishDraft)))
+	mux.HandleFunc("POST /api/media", cfg.getUserMiddleware(cfg.requireActiveUser(cfg.uploadMedia)))
+	mux.HandleFunc("GET /media/{id}", cfg.getOptionalUserMiddleware(cfg.getMedia))
+	mux.HandleFunc("GET /media/{id}/thumbnail", cfg.getOptionalUserMiddleware(cfg.getMediaThumbnail))
+	mux.HandleFunc("GET /api/trends", cfg.getTrends)
+	mux.HandleFunc("GET /api/hashtags/{tag}/chirps", cfg.getOptionalUserMiddleware(cfg.getHashtagChirps))
 	mux.HandleFunc("POST /api/polka/webhooks", cfg.polkaWebhook)
 	mux.HandleFunc("POST /api/users", cfg.addUser)
 	mux.HandleFunc("PUT /api/users", cfg.getUserMiddleware(cfg.updateUser))
+	mux.HandleFunc("PUT /api/users/preferences", cfg.getUserMiddleware(cfg.setPreferences))
+	mux.HandleFunc("PUT /api/users/pin", cfg.getUserMiddleware(cfg.setPins))
+	mux.HandleFunc("POST /api/users/export", cfg.getUserMiddleware(cfg.addExport))
+	mux.HandleFunc("GET /api/users/export/{id}", cfg.getUserMiddleware(cfg.getExport))
+	mux.HandleFunc("GET /api/exports/{id}/download", cfg.downloadExport)
+	mux.HandleFunc("POST /api/users/{id}/follow", cfg.getUserMiddleware(cfg.requireActiveUser(cfg.addFollow)))
+	mux.HandleFunc("DELETE /api/users/{id}/follow", cfg.getUserMiddleware(cfg.deleteFollow))
+	mux.HandleFunc("GET /api/users/{id}/mentions", cfg.getOptionalUserMiddleware(cfg.getUserMentions))
 	mux.HandleFunc("POST /api/login", cfg.login)
 	mux.HandleFunc("POST /api/refresh", cfg.refresh)
 	mux.HandleFunc("POST /api/revoke", cfg.revoke)
 	mux.HandleFunc("GET /admin/metrics", cfg.hitsMetricsHandler)
 	mux.HandleFunc("POST /admin/reset", cfg.resetHandler)
+	mux.HandleFunc("GET /admin/filters", cfg.adminMiddleware(cfg.getFilterWords))
+	mux.HandleFunc("POST /admin/filters", cfg.adminMiddleware(cfg.addFilterWord))
+	mux.HandleFunc("PUT /admin/filters/{id}", cfg.adminMiddleware(cfg.updateFilterWord))
+	mux.HandleFunc("DELETE /admin/filters/{id}", cfg.adminMiddleware(cfg.deleteFilterWord))
+	mux.HandleFunc("PUT /admin/chirps/{chirpID}/labels", cfg.adminMiddleware(cfg.labelChirp))
+	mux.HandleFunc("GET /admin/moderation", cfg.adminMiddleware(cfg.getModerationQueue))
+	mux.HandleFunc("GET /admin/moderation/appeals", cfg.adminMiddleware(cfg.getModerationAppeals))
+	mux.HandleFunc("GET /admin/moderation/{chirpID}", cfg.adminMiddleware(cfg.getModerationCase))
+	mux.HandleFunc("POST /admin/moderation/{chirpID}/decisions", cfg.adminMiddleware(cfg.addModerationDecision))
+	mux.HandleFunc("DELETE /admin/users/{id}/suspension", cfg.adminMiddleware(cfg.unsuspendUser))
 	mux.HandleFunc("GET /api/healthz", healthcheck)
+	go cfg.purgeDeletedChirps(time.Hour)
+	go cfg.backfillEntities()
+	go cfg.saveTrends(time.Minute)
+	go cfg.cleanUpExports(time.Minute)
+	go cfg.publishScheduledChirps(30 * time.Second)
 	server := http.Server{Handler: mux, Addr: ":8080"}
 	server.ListenAndServe()
 }
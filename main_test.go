@@ -0,0 +1,29 @@
+package main
+
+import "testing"
+
+func TestDirInside(t *testing.T) {
+	tests := []struct {
+		dir  string
+		root string
+		want bool
+	}{
+		{dir: "./static", root: "./static", want: true},
+		{dir: "./static/media", root: "./static", want: true},
+		{dir: "static/../static/exports", root: "./static", want: true},
+		{dir: "./media", root: "./static", want: false},
+		{dir: "./static-files", root: "./static", want: false},
+		{dir: "..", root: "./static", want: false},
+		{dir: "/var/lib/chirpy/media", root: "./static", want: false},
+	}
+	for _, test := range tests {
+		got, err := dirInside(test.dir, test.root)
+		if err != nil {
+			t.Errorf("%q: unexpected error: %s", test.dir, err)
+			continue
+		}
+		if got != test.want {
+			t.Errorf("%q in %q: expected %v, got %v", test.dir, test.root, test.want, got)
+		}
+	}
+}
@@ -0,0 +1,263 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
+	"errors"
+	"io"
+	"log"
+	"net/http"
+	"unicode/utf8"
+
+	"github.com/cameronbarnes/go_chirpy/internal/database"
+	"github.com/cameronbarnes/go_chirpy/internal/media"
+	"github.com/cameronbarnes/go_chirpy/internal/storage"
+	"github.com/google/uuid"
+)
+
+const (
+	maxMediaSize     = 5 << 20
+	maxMediaPerChirp = 4
+	maxAltTextLength = 1000
+	thumbnailSize    = 320
+)
+
+var (
+	errMediaNotFound = errors.New("Media not found or already attached")
+	errMediaHidden   = errors.New("Media is not visible to the viewer")
+)
+
+type mediaOut struct {
+	ID           uuid.UUID `json:"id"`
+	URL          string    `json:"url"`
+	ThumbnailURL string    `json:"thumbnail_url"`
+	AltText      string    `json:"alt_text"`
+	MimeType     string    `json:"mime_type"`
+	Width        int32     `json:"width"`
+	Height       int32     `json:"height"`
+}
+
+func mediaOutFrom(item database.Medium) mediaOut {
+	return mediaOut{
+		ID:           item.ID,
+		URL:          "/media/" + item.ID.String(),
+		ThumbnailURL: "/media/" + item.ID.String() + "/thumbnail",
+		AltText:      item.AltText,
+		MimeType:     item.MimeType,
+		Width:        item.Width,
+		Height:       item.Height,
+	}
+}
+
+func hashKey(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+func (c *apiConfig) uploadMedia(w http.ResponseWriter, r *http.Request, user database.GetUserRow) {
+	// Leave some room for the other form fields on top of the file itself.
+	r.Body = http.MaxBytesReader(w, r.Body, maxMediaSize+(1<<20))
+	file, _, err := r.FormFile("file")
+	if err != nil {
+		var tooLarge *http.MaxBytesError
+		if errors.As(err, &tooLarge) {
+			respondWithError(w, 413, "Images must be 5MB or smaller")
+			return
+		}
+		respondWithError(w, 400, "Request must be multipart with a file field")
+		return
+	}
+	defer file.Close()
+	data, err := io.ReadAll(io.LimitReader(file, maxMediaSize+1))
+	if err != nil {
+		respondWithError(w, 400, "Failed to read upload")
+		return
+	}
+	if len(data) > maxMediaSize {
+		respondWithError(w, 413, "Images must be 5MB or smaller")
+		return
+	}
+	altText := r.FormValue("alt_text")
+	if utf8.RuneCountInString(altText) > maxAltTextLength {
+		respondWithError(w, 400, "Alt text is too long")
+		return
+	}
+	info, err := media.Inspect(data)
+	if errors.Is(err, media.ErrUnsupportedType) {
+		respondWithError(w, 415, err.Error())
+		return
+	}
+	if errors.Is(err, media.ErrTooManyPixels) {
+		respondWithError(w, 413, err.Error())
+		return
+	}
+	if err != nil {
+		respondWithError(w, 400, "Image could not be decoded")
+		return
+	}
+	thumb, thumbType, err := media.Thumbnail(data, thumbnailSize)
+	if err != nil {
+		log.Printf("Failed to create thumbnail with err: %s", err)
+		respondWithError(w, 500, "Failed to process image")
+		return
+	}
+	key, thumbKey := hashKey(data), hashKey(thumb)
+	if err := c.media.Put(context.Background(), key, bytes.NewReader(data)); err != nil {
+		log.Printf("Failed to store media with err: %s", err)
+		respondWithError(w, 500, "Failed to store image")
+		return
+	}
+	if err := c.media.Put(context.Background(), thumbKey, bytes.NewReader(thumb)); err != nil {
+		log.Printf("Failed to store thumbnail with err: %s", err)
+		respondWithError(w, 500, "Failed to store image")
+		return
+	}
+	item, err := c.db.CreateMedia(context.Background(), database.CreateMediaParams{
+		UserID:            user.ID,
+		Sha256:            key,
+		MimeType:          info.MimeType,
+		SizeBytes:         int64(len(data)),
+		Width:             int32(info.Width),
+		Height:            int32(info.Height),
+		ThumbnailSha256:   thumbKey,
+		ThumbnailMimeType: thumbType,
+		AltText:           altText,
+	})
+	if err != nil {
+		log.Printf("Failed to save media with err: %s", err)
+		respondWithError(w, 500, "Failed to store image")
+		return
+	}
+	respondWithJSON(w, 201, mediaOutFrom(item))
+}
+
+func (c *apiConfig) getMedia(w http.ResponseWriter, r *http.Request, viewer *database.GetUserRow) {
+	c.serveMedia(w, r, viewer, false)
+}
+
+func (c *apiConfig) getMediaThumbnail(w http.ResponseWriter, r *http.Request, viewer *database.GetUserRow) {
+	c.serveMedia(w, r, viewer, true)
+}
+
+// serveMedia only serves media to viewers who can see the chirp it's attached
+// to. Uploads that aren't attached yet are only served to the uploader.
+func (c *apiConfig) serveMedia(w http.ResponseWriter, r *http.Request, viewer *database.GetUserRow, thumbnail bool) {
+	id, err := uuid.Parse(r.PathValue("id"))
+	if err != nil {
+		respondWithError(w, 400, "UUID provided is not valid")
+		return
+	}
+	item, err := c.db.GetMedia(context.Background(), id)
+	if err != nil {
+		respondWithError(w, 404, "Media Not Found")
+		return
+	}
+	public, err := c.mediaVisibleTo(item, viewer)
+	if errors.Is(err, errMediaHidden) {
+		respondWithError(w, 404, "Media Not Found")
+		return
+	}
+	if err != nil {
+		log.Printf("Failed to check media visibility with err: %s", err)
+		respondWithError(w, 500, "Failed to get media")
+		return
+	}
+	key, mimeType := item.Sha256, item.MimeType
+	if thumbnail {
+		key, mimeType = item.ThumbnailSha256, item.ThumbnailMimeType
+	}
+	file, err := c.media.Open(context.Background(), key)
+	if errors.Is(err, storage.ErrNotFound) {
+		respondWithError(w, 404, "Media Not Found")
+		return
+	}
+	if err != nil {
+		log.Printf("Failed to open media with err: %s", err)
+		respondWithError(w, 500, "Failed to get media")
+		return
+	}
+	defer file.Close()
+	data, err := io.ReadAll(file)
+	if err != nil {
+		log.Printf("Failed to read media with err: %s", err)
+		respondWithError(w, 500, "Failed to get media")
+		return
+	}
+	w.Header().Set("Content-Type", mimeType)
+	if public {
+		// The bytes never change, but the chirp can still be deleted or made
+		// private, so caches only keep it briefly.
+		w.Header().Set("Cache-Control", "public, max-age=300")
+	} else {
+		// Who can see this may change, so shared caches mustn't keep it.
+		w.Header().Set("Cache-Control", "private, no-store")
+	}
+	http.ServeContent(w, r, "", item.CreatedAt, bytes.NewReader(data))
+}
+
+// mediaVisibleTo returns errMediaHidden unless viewer can see the chirp the
+// media is attached to, applying the same rules as the chirp itself. It
+// reports whether the media is public, and so fine for shared caches.
+func (c *apiConfig) mediaVisibleTo(item database.Medium, viewer *database.GetUserRow) (bool, error) {
+	if !item.ChirpID.Valid {
+		if viewer == nil || viewer.ID != item.UserID {
+			return false, errMediaHidden
+		}
+		return false, nil
+	}
+	chirp, err := c.db.GetChirp(context.Background(), item.ChirpID.UUID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return false, errMediaHidden
+	}
+	if err != nil {
+		return false, err
+	}
+	if chirpRemoved(chirp) {
+		return false, errMediaHidden
+	}
+	visible, err := c.chirpVisibleTo(chirp, viewer)
+	if err != nil {
+		return false, err
+	}
+	if !visible {
+		return false, errMediaHidden
+	}
+	return chirpPublic(chirp), nil
+}
+
+// attachMedia links uploaded images to a new chirp in the order given. Only
+// the uploader's own, not yet attached images can be used.
+func attachMedia(q *database.Queries, chirp database.Chirp, ids []uuid.UUID) error {
+	for i, id := range ids {
+		_, err := q.AttachMedia(context.Background(), database.AttachMediaParams{
+			ChirpID:  uuid.NullUUID{UUID: chirp.ID, Valid: true},
+			Position: int32(i),
+			ID:       id,
+			UserID:   chirp.UserID,
+		})
+		if errors.Is(err, sql.ErrNoRows) {
+			return errMediaNotFound
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func validateMediaIDs(ids []uuid.UUID) error {
+	if len(ids) > maxMediaPerChirp {
+		return errors.New("Chirps can have at most 4 images")
+	}
+	seen := map[uuid.UUID]bool{}
+	for _, id := range ids {
+		if seen[id] {
+			return errors.New("Each image can only be attached once")
+		}
+		seen[id] = true
+	}
+	return nil
+}
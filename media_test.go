@@ -0,0 +1,32 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/google/uuid"
+)
+
+func TestValidateMediaIDs(t *testing.T) {
+	a, b := uuid.New(), uuid.New()
+	tests := []struct {
+		name    string
+		ids     []uuid.UUID
+		wantErr bool
+	}{
+		{name: "none"},
+		{name: "empty list", ids: []uuid.UUID{}},
+		{name: "one", ids: []uuid.UUID{a}},
+		{name: "at the limit", ids: []uuid.UUID{uuid.New(), uuid.New(), uuid.New(), uuid.New()}},
+		{name: "over the limit", ids: []uuid.UUID{uuid.New(), uuid.New(), uuid.New(), uuid.New(), uuid.New()}, wantErr: true},
+		{name: "duplicate", ids: []uuid.UUID{a, b, a}, wantErr: true},
+	}
+	for _, test := range tests {
+		err := validateMediaIDs(test.ids)
+		if test.wantErr && err == nil {
+			t.Errorf("%s: expected an error", test.name)
+		}
+		if !test.wantErr && err != nil {
+			t.Errorf("%s: unexpected error: %s", test.name, err)
+		}
+	}
+}
@@ -0,0 +1,51 @@
+package main
+
+import (
+	"context"
+	"log"
+	"net/http"
+
+	"github.com/cameronbarnes/go_chirpy/internal/chirptext"
+	"github.com/cameronbarnes/go_chirpy/internal/database"
+	"github.com/google/uuid"
+)
+
+// saveMentions links the chirp to every mentioned handle that belongs to a
+// user. Handles nobody has claimed are left as plain text.
+func saveMentions(q *database.Queries, chirp database.Chirp) error {
+	handles := chirptext.Mentions(chirp.Body)
+	if len(handles) == 0 {
+		return nil
+	}
+	users, err := q.GetUsersByHandles(context.Background(), handles)
+	if err != nil {
+		return err
+	}
+	for _, user := range users {
+		err := q.AddChirpMention(context.Background(), database.AddChirpMentionParams{ChirpID: chirp.ID, UserID: user.ID})
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (c *apiConfig) getUserMentions(w http.ResponseWriter, r *http.Request, viewer *database.GetUserRow) {
+	id, err := uuid.Parse(r.PathValue("id"))
+	if err != nil {
+		respondWithError(w, 400, "UUID provided is not valid")
+		return
+	}
+	p, err := parsePage(r)
+	if err != nil {
+		respondWithError(w, 400, err.Error())
+		return
+	}
+	chirps, err := c.db.ListChirpsMentioningUser(context.Background(), database.ListChirpsMentioningUserParams{UserID: id, AfterCreatedAt: p.AfterCreatedAt, AfterID: p.AfterID, ViewerID: viewerID(viewer), PageSize: p.fetchLimit()})
+	if err != nil {
+		log.Printf("Failed to get mentions with err: %s", err)
+		respondWithError(w, 500, "Failed to get chirps")
+		return
+	}
+	c.respondWithChirps(w, 200, pageChirps(w, r, p, chirps), viewer)
+}
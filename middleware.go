@@ -30,3 +30,44 @@ func (c *apiConfig) getUserMiddleware(next func(w http.ResponseWriter, r *http.R
 		next(w, r, user)
 	}
 }
+
+// getOptionalUserMiddleware is for endpoints that anyone can read but that
+// show more to a signed in user. A missing token means an anonymous caller,
+// while a bad token is still rejected so clients notice expired sessions.
+func (c *apiConfig) getOptionalUserMiddleware(next func(w http.ResponseWriter, r *http.Request, viewer *database.GetUserRow)) func(http.ResponseWriter, *http.Request) {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") == "" {
+			next(w, r, nil)
+			return
+		}
+		c.getUserMiddleware(func(w http.ResponseWriter, r *http.Request, user database.GetUserRow) {
+			next(w, r, &user)
+		})(w, r)
+	}
+}
+
+// adminMiddleware guards the admin API with the ADMIN_KEY shared secret, sent
+// the same way Polka sends its key. With no key configured every request is
+// refused.
+func (c *apiConfig) adminMiddleware(next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		key, err := auth.GetAPIKey(r.Header)
+		if err != nil || c.adminKey == "" || key != c.adminKey {
+			respondWithError(w, 401, "Unauthorized")
+			return
+		}
+		next(w, r)
+	}
+}
+
+// requireActiveUser keeps suspended users from posting or interacting. They
+// can still sign in, read and appeal, so it only wraps the write endpoints.
+func (c *apiConfig) requireActiveUser(next func(w http.ResponseWriter, r *http.Request, user database.GetUserRow)) func(w http.ResponseWriter, r *http.Request, user database.GetUserRow) {
+	return func(w http.ResponseWriter, r *http.Request, user database.GetUserRow) {
+		if user.SuspendedAt.Valid {
+			respondWithError(w, 403, "Your account is suspended")
+			return
+		}
+		next(w, r, user)
+	}
+}
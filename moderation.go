@@ -0,0 +1,420 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/cameronbarnes/go_chirpy/internal/chirptext"
+	"github.com/cameronbarnes/go_chirpy/internal/database"
+	"github.com/google/uuid"
+)
+
+const maxReportNoteLength = 1000
+
+var reportReasons = map[string]bool{
+	"spam":           true,
+	"harassment":     true,
+	"hate":           true,
+	"violence":       true,
+	"sexual":         true,
+	"misinformation": true,
+	"other":          true,
+}
+
+// Decisions recorded against a chirp. Moderators choose all but autoHide,
+// which is recorded when enough reports come in, and label, which is recorded
+// when labels are changed through labelChirp.
+const (
+	decisionDismiss  = "dismiss"
+	decisionHide     = "hide"
+	decisionSuspend  = "suspend"
+	decisionRestore  = "restore"
+	decisionUphold   = "uphold"
+	decisionAutoHide = "auto_hide"
+	decisionLabel    = "label"
+)
+
+var errNoOpenAppeal = errors.New("There is no open appeal for this Chirp")
+
+type queueItemOut struct {
+	ChirpID     uuid.UUID    `json:"chirp_id"`
+	UserID      uuid.UUID    `json:"user_id"`
+	Body        string       `json:"body"`
+	Flagged     bool         `json:"flagged"`
+	HiddenAt    sql.NullTime `json:"hidden_at"`
+	ReportCount int64        `json:"report_count"`
+	Reasons     []string     `json:"reasons"`
+	QueuedAt    time.Time    `json:"queued_at"`
+}
+
+func (c *apiConfig) addReport(w http.ResponseWriter, r *http.Request, user database.GetUserRow) {
+	type reportArgs struct {
+		Reason string `json:"reason"`
+		Note   string `json:"note"`
+	}
+	id, err := uuid.Parse(r.PathValue("chirpID"))
+	if err != nil {
+		respondWithError(w, 400, "UUID provided is not valid")
+		return
+	}
+	arg, err := handleParse[reportArgs](w, r)
+	if err != nil {
+		return
+	}
+	if !reportReasons[arg.Reason] {
+		respondWithError(w, 400, "Reason must be one of spam, harassment, hate, violence, sexual, misinformation or other")
+		return
+	}
+	if len([]rune(arg.Note)) > maxReportNoteLength {
+		respondWithError(w, 400, "Note is too long")
+		return
+	}
+	chirp, err := c.getOriginalChirp(id, &user)
+	if err != nil {
+		respondWithError(w, 404, "Chirp Not Found")
+		return
+	}
+	if chirp.UserID == user.ID {
+		respondWithError(w, 400, "You can't report your own Chirp")
+		return
+	}
+	var report database.ChirpReport
+	err = c.withTx(func(q *database.Queries) error {
+		report, err = q.AddReport(context.Background(), database.AddReportParams{ChirpID: chirp.ID, ReporterID: user.ID, Reason: arg.Reason, Note: arg.Note})
+		if err != nil {
+			return err
+		}
+		count, err := q.CountOpenReports(context.Background(), chirp.ID)
+		if err != nil {
+			return err
+		}
+		if count < int64(c.reportHideThreshold) || chirp.HiddenAt.Valid {
+			return nil
+		}
+		if err := q.HideChirp(context.Background(), chirp.ID); err != nil {
+			return err
+		}
+		_, err = q.AddModerationDecision(context.Background(), database.AddModerationDecisionParams{
+			ChirpID: chirp.ID,
+			Action:  decisionAutoHide,
+			Note:    fmt.Sprintf("Hidden automatically after %d reports", count),
+		})
+		return err
+	})
+	if isUniqueViolation(err) {
+		respondWithError(w, 409, "You have already reported this Chirp")
+		return
+	}
+	if err != nil {
+		log.Printf("Failed to report chirp with err: %s", err)
+		respondWithError(w, 500, "Failed to report Chirp")
+		return
+	}
+	respondWithJSON(w, 201, report)
+}
+
+// addAppeal lets an author ask for a hidden chirp to be looked at again. Each
+// chirp can only be appealed once, whatever the outcome.
+func (c *apiConfig) addAppeal(w http.ResponseWriter, r *http.Request, user database.GetUserRow) {
+	type appealArgs struct {
+		Note string `json:"note"`
+	}
+	chirp, ok := c.getOwnedChirp(w, r, user)
+	if !ok {
+		return
+	}
+	arg, err := handleParse[appealArgs](w, r)
+	if err != nil {
+		return
+	}
+	if len([]rune(arg.Note)) > maxReportNoteLength {
+		respondWithError(w, 400, "Note is too long")
+		return
+	}
+	if !chirp.HiddenAt.Valid {
+		respondWithError(w, 400, "Only hidden Chirps can be appealed")
+		return
+	}
+	appeal, err := c.db.AddAppeal(context.Background(), database.AddAppealParams{ChirpID: chirp.ID, UserID: user.ID, Note: arg.Note})
+	if isUniqueViolation(err) {
+		respondWithError(w, 409, "This Chirp has already been appealed")
+		return
+	}
+	if err != nil {
+		log.Printf("Failed to appeal chirp with err: %s", err)
+		respondWithError(w, 500, "Failed to appeal Chirp")
+		return
+	}
+	respondWithJSON(w, 201, appeal)
+}
+
+// getModerationQueue lists chirps with open reports or flagged by the chirp
+// pipeline, most reported first. Moderators work from the top, so there's a
+// limit but no cursor.
+func (c *apiConfig) getModerationQueue(w http.ResponseWriter, r *http.Request) {
+	// The queue is ordered by report count, which changes as reports come in
+	// and get resolved, so there's no stable place for a cursor to resume.
+	if r.URL.Query().Has("cursor") {
+		respondWithError(w, 400, "The moderation queue can't be paged with a cursor, resolve cases to see more")
+		return
+	}
+	p, err := parsePage(r)
+	if err != nil {
+		respondWithError(w, 400, err.Error())
+		return
+	}
+	items, err := c.db.ListModerationQueue(context.Background(), p.Size)
+	if err != nil {
+		log.Printf("Failed to list moderation queue with err: %s", err)
+		respondWithError(w, 500, "Failed to get moderation queue")
+		return
+	}
+	out := make([]queueItemOut, len(items))
+	for i, item := range items {
+		out[i] = queueItemOut{
+			ChirpID:     item.ChirpID,
+			UserID:      item.UserID,
+			Body:        item.Body,
+			Flagged:     item.Flagged,
+			HiddenAt:    item.HiddenAt,
+			ReportCount: item.ReportCount,
+			Reasons:     item.Reasons,
+			QueuedAt:    item.QueuedAt,
+		}
+	}
+	respondWithJSON(w, 200, out)
+}
+
+func (c *apiConfig) getModerationAppeals(w http.ResponseWriter, r *http.Request) {
+	// Appeals are worked through oldest first and drop out once resolved, so
+	// the first page is always the next batch to look at.
+	if r.URL.Query().Has("cursor") {
+		respondWithError(w, 400, "Appeals can't be paged with a cursor, resolve appeals to see more")
+		return
+	}
+	p, err := parsePage(r)
+	if err != nil {
+		respondWithError(w, 400, err.Error())
+		return
+	}
+	appeals, err := c.db.ListOpenAppeals(context.Background(), p.Size)
+	if err != nil {
+		log.Printf("Failed to list appeals with err: %s", err)
+		respondWithError(w, 500, "Failed to get appeals")
+		return
+	}
+	if appeals == nil {
+		appeals = []database.ChirpAppeal{}
+	}
+	respondWithJSON(w, 200, appeals)
+}
+
+// getModerationCase shows everything a moderator needs to decide on a chirp:
+// the chirp itself, every report, earlier decisions and any appeal.
+func (c *apiConfig) getModerationCase(w http.ResponseWriter, r *http.Request) {
+	type caseOut struct {
+		Chirp     database.Chirp                `json:"chirp"`
+		Flagged   bool                          `json:"flagged"`
+		Reports   []database.ChirpReport        `json:"reports"`
+		Decisions []database.ModerationDecision `json:"decisions"`
+		Appeal    *database.ChirpAppeal         `json:"appeal"`
+	}
+	id, err := uuid.Parse(r.PathValue("chirpID"))
+	if err != nil {
+		respondWithError(w, 400, "UUID provided is not valid")
+		return
+	}
+	chirp, err := c.db.GetChirp(context.Background(), id)
+	if err != nil {
+		respondWithError(w, 404, "Chirp Not Found")
+		return
+	}
+	out := caseOut{Chirp: chirp, Flagged: chirp.Flagged, Reports: []database.ChirpReport{}, Decisions: []database.ModerationDecision{}}
+	reports, err := c.db.ListReportsForChirp(context.Background(), id)
+	if err != nil {
+		log.Printf("Failed to list reports with err: %s", err)
+		respondWithError(w, 500, "Failed to get moderation case")
+		return
+	}
+	out.Reports = append(out.Reports, reports...)
+	decisions, err := c.db.ListDecisionsForChirp(context.Background(), id)
+	if err != nil {
+		log.Printf("Failed to list decisions with err: %s", err)
+		respondWithError(w, 500, "Failed to get moderation case")
+		return
+	}
+	out.Decisions = append(out.Decisions, decisions...)
+	appeal, err := c.db.GetAppeal(context.Background(), id)
+	if err == nil {
+		out.Appeal = &appeal
+	} else if !errors.Is(err, sql.ErrNoRows) {
+		log.Printf("Failed to get appeal with err: %s", err)
+		respondWithError(w, 500, "Failed to get moderation case")
+		return
+	}
+	respondWithJSON(w, 200, out)
+}
+
+// addModerationDecision applies a moderator's decision and records it. The
+// decision and its effects share a transaction so the log always matches
+// what happened.
+func (c *apiConfig) addModerationDecision(w http.ResponseWriter, r *http.Request) {
+	type decisionArgs struct {
+		Action string `json:"action"`
+		Note   string `json:"note"`
+	}
+	id, err := uuid.Parse(r.PathValue("chirpID"))
+	if err != nil {
+		respondWithError(w, 400, "UUID provided is not valid")
+		return
+	}
+	arg, err := handleParse[decisionArgs](w, r)
+	if err != nil {
+		return
+	}
+	chirp, err := c.db.GetChirp(context.Background(), id)
+	if err != nil {
+		respondWithError(w, 404, "Chirp Not Found")
+		return
+	}
+	var apply func(q *database.Queries) error
+	switch arg.Action {
+	case decisionDismiss:
+		apply = func(q *database.Queries) error {
+			if err := q.ResolveReports(context.Background(), database.ResolveReportsParams{ChirpID: chirp.ID, Status: "dismissed"}); err != nil {
+				return err
+			}
+			if err := q.ClearChirpFlag(context.Background(), chirp.ID); err != nil {
+				return err
+			}
+			return q.UnhideChirp(context.Background(), chirp.ID)
+		}
+	case decisionHide, decisionSuspend:
+		apply = func(q *database.Queries) error {
+			if err := q.ResolveReports(context.Background(), database.ResolveReportsParams{ChirpID: chirp.ID, Status: "actioned"}); err != nil {
+				return err
+			}
+			if err := q.ClearChirpFlag(context.Background(), chirp.ID); err != nil {
+				return err
+			}
+			if err := q.HideChirp(context.Background(), chirp.ID); err != nil {
+				return err
+			}
+			if arg.Action == decisionSuspend {
+				return q.SuspendUser(context.Background(), chirp.UserID)
+			}
+			return nil
+		}
+	case decisionRestore:
+		// Reverses an earlier hide, usually in answer to an appeal. A
+		// suspension may rest on other chirps too, so it's lifted separately
+		// with unsuspendUser.
+		apply = func(q *database.Queries) error {
+			if err := q.ResolveAppeal(context.Background(), database.ResolveAppealParams{ChirpID: chirp.ID, Status: "accepted"}); err != nil {
+				return err
+			}
+			return q.UnhideChirp(context.Background(), chirp.ID)
+		}
+	case decisionUphold:
+		apply = func(q *database.Queries) error {
+			appeal, err := q.GetAppeal(context.Background(), chirp.ID)
+			if errors.Is(err, sql.ErrNoRows) || (err == nil && appeal.Status != "open") {
+				return errNoOpenAppeal
+			}
+			if err != nil {
+				return err
+			}
+			return q.ResolveAppeal(context.Background(), database.ResolveAppealParams{ChirpID: chirp.ID, Status: "rejected"})
+		}
+	default:
+		respondWithError(w, 400, "Action must be dismiss, hide, suspend, restore or uphold")
+		return
+	}
+	var decision database.ModerationDecision
+	err = c.withTx(func(q *database.Queries) error {
+		if err := apply(q); err != nil {
+			return err
+		}
+		decision, err = q.AddModerationDecision(context.Background(), database.AddModerationDecisionParams{ChirpID: chirp.ID, Action: arg.Action, Note: arg.Note})
+		return err
+	})
+	if errors.Is(err, errNoOpenAppeal) {
+		respondWithError(w, 409, err.Error())
+		return
+	}
+	if err != nil {
+		log.Printf("Failed to apply moderation decision with err: %s", err)
+		respondWithError(w, 500, "Failed to apply decision")
+		return
+	}
+	respondWithJSON(w, 201, decision)
+}
+
+// unsuspendUser lifts a user's suspension. It's kept apart from decisions on
+// chirps since a suspension is about the user, not any one chirp.
+func (c *apiConfig) unsuspendUser(w http.ResponseWriter, r *http.Request) {
+	id, err := uuid.Parse(r.PathValue("id"))
+	if err != nil {
+		respondWithError(w, 400, "UUID provided is not valid")
+		return
+	}
+	if _, err := c.db.GetUser(context.Background(), id); err != nil {
+		respondWithError(w, 404, "User Not Found")
+		return
+	}
+	if err := c.db.UnsuspendUser(context.Background(), id); err != nil {
+		log.Printf("Failed to unsuspend user with err: %s", err)
+		respondWithError(w, 500, "Failed to lift suspension")
+		return
+	}
+	w.WriteHeader(204)
+}
+
+// labelChirp sets a chirp's content warning and sensitive label after the
+// fact, replacing whatever the author chose.
+func (c *apiConfig) labelChirp(w http.ResponseWriter, r *http.Request) {
+	type labelArgs struct {
+		ContentWarning string `json:"content_warning"`
+		Sensitive      bool   `json:"sensitive"`
+		Note           string `json:"note"`
+	}
+	id, err := uuid.Parse(r.PathValue("chirpID"))
+	if err != nil {
+		respondWithError(w, 400, "UUID provided is not valid")
+		return
+	}
+	arg, err := handleParse[labelArgs](w, r)
+	if err != nil {
+		return
+	}
+	arg.ContentWarning = strings.TrimSpace(arg.ContentWarning)
+	if chirptext.Length(arg.ContentWarning) > maxContentWarningLength {
+		respondWithError(w, 400, fmt.Sprintf("Content warning must be at most %d characters", maxContentWarningLength))
+		return
+	}
+	var chirp database.Chirp
+	err = c.withTx(func(q *database.Queries) error {
+		chirp, err = q.LabelChirp(context.Background(), database.LabelChirpParams{ID: id, ContentWarning: arg.ContentWarning, Sensitive: arg.Sensitive})
+		if err != nil {
+			return err
+		}
+		_, err = q.AddModerationDecision(context.Background(), database.AddModerationDecisionParams{ChirpID: id, Action: decisionLabel, Note: arg.Note})
+		return err
+	})
+	if errors.Is(err, sql.ErrNoRows) {
+		respondWithError(w, 404, "Chirp Not Found")
+		return
+	}
+	if err != nil {
+		log.Printf("Failed to label chirp with err: %s", err)
+		respondWithError(w, 500, "Failed to label Chirp")
+		return
+	}
+	respondWithJSON(w, 200, chirp)
+}
@@ -0,0 +1,25 @@
+package main
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+func TestModerationListsRejectCursor(t *testing.T) {
+	c := &apiConfig{}
+	cursor := encodeCursor(time.Now(), uuid.New())
+	for path, handler := range map[string]http.HandlerFunc{
+		"/admin/moderation":         c.getModerationQueue,
+		"/admin/moderation/appeals": c.getModerationAppeals,
+	} {
+		w := httptest.NewRecorder()
+		handler(w, httptest.NewRequest("GET", path+"?cursor="+cursor, nil))
+		if w.Code != 400 {
+			t.Errorf("%s: expected 400, got %d", path, w.Code)
+		}
+	}
+}
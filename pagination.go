@@ -0,0 +1,131 @@
+package main
+
+import (
+	"database/sql"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/cameronbarnes/go_chirpy/internal/database"
+	"github.com/google/uuid"
+)
+
+const (
+	defaultPageSize = 50
+	maxPageSize     = 100
+)
+
+type page struct {
+	Size           int32
+	AfterCreatedAt sql.NullTime
+	AfterID        uuid.NullUUID
+}
+
+func parsePage(r *http.Request) (page, error) {
+	out := page{Size: defaultPageSize}
+	query := r.URL.Query()
+	if limit := query.Get("limit"); limit != "" {
+		size, err := strconv.Atoi(limit)
+		if err != nil || size < 1 {
+			return out, errors.New("Limit must be a positive integer")
+		}
+		out.Size = int32(min(size, maxPageSize))
+	}
+	if cursor := query.Get("cursor"); cursor != "" {
+		createdAt, id, err := decodeCursor(cursor)
+		if err != nil {
+			return out, errors.New("Cursor is not valid")
+		}
+		out.AfterCreatedAt = sql.NullTime{Time: createdAt, Valid: true}
+		out.AfterID = uuid.NullUUID{UUID: id, Valid: true}
+	}
+	return out, nil
+}
+
+// fetchLimit asks the database for one row more than the page size so we
+// can tell whether another page exists without a separate count query.
+func (p page) fetchLimit() int32 {
+	return p.Size + 1
+}
+
+func parseTimeRange(r *http.Request) (sql.NullTime, sql.NullTime, error) {
+	since, err := parseTimeParam(r, "since")
+	if err != nil {
+		return since, sql.NullTime{}, err
+	}
+	until, err := parseTimeParam(r, "until")
+	if err != nil {
+		return since, until, err
+	}
+	if since.Valid && until.Valid && !since.Time.Before(until.Time) {
+		return since, until, errors.New("Since must be before until")
+	}
+	return since, until, nil
+}
+
+// parseTimeParam reads an RFC 3339 query parameter. Timestamps are stored
+// without a zone in UTC, so the value is converted before it reaches SQL.
+func parseTimeParam(r *http.Request, name string) (sql.NullTime, error) {
+	val := r.URL.Query().Get(name)
+	if val == "" {
+		return sql.NullTime{}, nil
+	}
+	parsed, err := time.Parse(time.RFC3339, val)
+	if err != nil {
+		return sql.NullTime{}, fmt.Errorf("%s must be an RFC 3339 timestamp", name)
+	}
+	return sql.NullTime{Time: parsed.UTC(), Valid: true}, nil
+}
+
+func encodeCursor(createdAt time.Time, id uuid.UUID) string {
+	raw := createdAt.UTC().Format(time.RFC3339Nano) + "," + id.String()
+	return base64.RawURLEncoding.EncodeToString([]byte(raw))
+}
+
+func decodeCursor(cursor string) (time.Time, uuid.UUID, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return time.Time{}, uuid.UUID{}, err
+	}
+	timeStr, idStr, found := strings.Cut(string(raw), ",")
+	if !found {
+		return time.Time{}, uuid.UUID{}, errors.New("Cursor is missing a separator")
+	}
+	createdAt, err := time.Parse(time.RFC3339Nano, timeStr)
+	if err != nil {
+		return time.Time{}, uuid.UUID{}, err
+	}
+	id, err := uuid.Parse(idStr)
+	if err != nil {
+		return time.Time{}, uuid.UUID{}, err
+	}
+	return createdAt, id, nil
+}
+
+func setNextLink(w http.ResponseWriter, r *http.Request, cursor string) {
+	query := r.URL.Query()
+	query.Set("cursor", cursor)
+	w.Header().Set("Link", fmt.Sprintf(`<%s?%s>; rel="next"`, r.URL.Path, query.Encode()))
+}
+
+// paginate trims the extra row requested by fetchLimit and, when it was
+// present, points the Link header at the next page. key returns the values
+// the listing is ordered by.
+func paginate[T any](w http.ResponseWriter, r *http.Request, p page, items []T, key func(T) (time.Time, uuid.UUID)) []T {
+	if len(items) <= int(p.Size) {
+		return items
+	}
+	items = items[:p.Size]
+	setNextLink(w, r, encodeCursor(key(items[len(items)-1])))
+	return items
+}
+
+func pageChirps(w http.ResponseWriter, r *http.Request, p page, chirps []database.Chirp) []database.Chirp {
+	return paginate(w, r, p, chirps, func(chirp database.Chirp) (time.Time, uuid.UUID) {
+		return chirp.CreatedAt, chirp.ID
+	})
+}
@@ -0,0 +1,147 @@
+package main
+
+import (
+	"encoding/base64"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+func TestCursorRoundTrip(t *testing.T) {
+	createdAt := time.Date(2025, 6, 1, 12, 30, 0, 123456789, time.UTC)
+	id := uuid.New()
+	gotTime, gotID, err := decodeCursor(encodeCursor(createdAt.In(time.FixedZone("EST", -5*3600)), id))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !gotTime.Equal(createdAt) || gotID != id {
+		t.Errorf("Expected %s %s, got %s %s", createdAt, id, gotTime, gotID)
+	}
+}
+
+func TestDecodeCursorRejectsMalformed(t *testing.T) {
+	encode := func(raw string) string { return base64.RawURLEncoding.EncodeToString([]byte(raw)) }
+	valid := encodeCursor(time.Now(), uuid.New())
+	tests := []struct {
+		name   string
+		cursor string
+	}{
+		{"not base64", "not a cursor!"},
+		{"padded base64", valid + "=="},
+		{"missing separator", encode("2025-06-01T12:00:00Z")},
+		{"bad time", encode("yesterday," + uuid.NewString())},
+		{"bad id", encode("2025-06-01T12:00:00Z,not-a-uuid")},
+		{"truncated", valid[:len(valid)-4]},
+		{"empty fields", encode(",")},
+	}
+	for _, test := range tests {
+		if _, _, err := decodeCursor(test.cursor); err == nil {
+			t.Errorf("%s: expected %q to be rejected", test.name, test.cursor)
+		}
+	}
+}
+
+func TestParsePage(t *testing.T) {
+	id := uuid.New()
+	createdAt := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+	tests := []struct {
+		query   string
+		size    int32
+		cursor  bool
+		wantErr bool
+	}{
+		{"", defaultPageSize, false, false},
+		{"limit=10", 10, false, false},
+		{"limit=1000", maxPageSize, false, false},
+		{"limit=0", 0, false, true},
+		{"limit=ten", 0, false, true},
+		{"cursor=" + encodeCursor(createdAt, id), defaultPageSize, true, false},
+		{"cursor=garbage", 0, false, true},
+	}
+	for _, test := range tests {
+		p, err := parsePage(httptest.NewRequest("GET", "/api/chirps?"+test.query, nil))
+		if test.wantErr {
+			if err == nil {
+				t.Errorf("%q: expected an error", test.query)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("%q: unexpected error: %s", test.query, err)
+			continue
+		}
+		if p.Size != test.size || p.AfterID.Valid != test.cursor {
+			t.Errorf("%q: unexpected page: %+v", test.query, p)
+		}
+		if test.cursor && (p.AfterID.UUID != id || !p.AfterCreatedAt.Time.Equal(createdAt)) {
+			t.Errorf("%q: cursor decoded to %+v", test.query, p)
+		}
+	}
+}
+
+func TestPaginateSetsNextLink(t *testing.T) {
+	items := []int{1, 2, 3}
+	key := func(i int) (time.Time, uuid.UUID) { return time.Unix(int64(i), 0), uuid.Nil }
+	w := httptest.NewRecorder()
+	got := paginate(w, httptest.NewRequest("GET", "/api/chirps?limit=2", nil), page{Size: 2}, items, key)
+	if len(got) != 2 {
+		t.Fatalf("Expected 2 items, got %d", len(got))
+	}
+	link := w.Header().Get("Link")
+	if !strings.Contains(link, "cursor="+encodeCursor(time.Unix(2, 0), uuid.Nil)) || !strings.HasSuffix(link, `rel="next"`) {
+		t.Errorf("Unexpected Link header: %q", link)
+	}
+	w = httptest.NewRecorder()
+	paginate(w, httptest.NewRequest("GET", "/api/chirps", nil), page{Size: 3}, items, key)
+	if link := w.Header().Get("Link"); link != "" {
+		t.Errorf("The last page shouldn't link onwards, got %q", link)
+	}
+}
+
+func TestParseTimeRange(t *testing.T) {
+	tests := []struct {
+		query   string
+		since   string
+		until   string
+		wantErr bool
+	}{
+		{query: ""},
+		{query: "since=2025-06-01T12:00:00Z", since: "2025-06-01T12:00:00Z"},
+		{query: "until=2025-06-01T14:00:00%2B02:00", until: "2025-06-01T12:00:00Z"},
+		{query: "since=2025-06-01T00:00:00Z&until=2025-06-02T00:00:00Z", since: "2025-06-01T00:00:00Z", until: "2025-06-02T00:00:00Z"},
+		{query: "since=2025-06-02T00:00:00Z&until=2025-06-01T00:00:00Z", wantErr: true},
+		{query: "since=2025-06-01T00:00:00Z&until=2025-06-01T00:00:00Z", wantErr: true},
+		{query: "since=2025-06-01", wantErr: true},
+		{query: "until=yesterday", wantErr: true},
+	}
+	for _, test := range tests {
+		since, until, err := parseTimeRange(httptest.NewRequest("GET", "/api/chirps?"+test.query, nil))
+		if test.wantErr {
+			if err == nil {
+				t.Errorf("%q: expected an error", test.query)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("%q: unexpected error: %s", test.query, err)
+			continue
+		}
+		for _, check := range []struct {
+			name string
+			got  time.Time
+			ok   bool
+			want string
+		}{{"since", since.Time, since.Valid, test.since}, {"until", until.Time, until.Valid, test.until}} {
+			if check.ok != (check.want != "") {
+				t.Errorf("%q: unexpected %s: %v", test.query, check.name, check.got)
+				continue
+			}
+			if check.ok && check.got.Format(time.RFC3339) != check.want {
+				t.Errorf("%q: expected %s %s, got %s", test.query, check.name, check.want, check.got.Format(time.RFC3339))
+			}
+		}
+	}
+}
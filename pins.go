@@ -0,0 +1,123 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+
+	"github.com/cameronbarnes/go_chirpy/internal/database"
+	"github.com/google/uuid"
+)
+
+const (
+	maxPins    = 1
+	maxPinsRed = 3
+)
+
+var errPinNotAllowed = errors.New("Only your own published Chirps can be pinned")
+
+// setPins replaces the caller's pinned chirps with the ones given, in order.
+// An empty list unpins everything.
+func (c *apiConfig) setPins(w http.ResponseWriter, r *http.Request, user database.GetUserRow) {
+	type pinArgs struct {
+		ChirpIDs []uuid.UUID `json:"chirp_ids"`
+	}
+	arg, err := handleParse[pinArgs](w, r)
+	if err != nil {
+		return
+	}
+	limit := maxPins
+	if user.IsChirpyRed {
+		limit = maxPinsRed
+	}
+	if len(arg.ChirpIDs) > limit {
+		respondWithError(w, 400, fmt.Sprintf("You can pin at most %d Chirps", limit))
+		return
+	}
+	seen := map[uuid.UUID]bool{}
+	for _, id := range arg.ChirpIDs {
+		if seen[id] {
+			respondWithError(w, 400, "Chirps can only be pinned once")
+			return
+		}
+		seen[id] = true
+	}
+	err = c.withTx(func(q *database.Queries) error {
+		if err := q.DeletePinsForUser(context.Background(), user.ID); err != nil {
+			return err
+		}
+		for i, id := range arg.ChirpIDs {
+			chirp, err := q.GetChirp(context.Background(), id)
+			if errors.Is(err, sql.ErrNoRows) {
+				return errPinNotAllowed
+			}
+			if err != nil {
+				return err
+			}
+			if chirp.UserID != user.ID || chirp.RechirpOf.Valid || chirpRemoved(chirp) || !chirp.Published || chirp.HiddenAt.Valid {
+				return errPinNotAllowed
+			}
+			err = q.AddPin(context.Background(), database.AddPinParams{UserID: user.ID, ChirpID: id, Position: int32(i)})
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if errors.Is(err, errPinNotAllowed) {
+		respondWithError(w, 400, err.Error())
+		return
+	}
+	if err != nil {
+		log.Printf("Failed to pin chirps with err: %s", err)
+		respondWithError(w, 500, "Failed to pin Chirps")
+		return
+	}
+	pinned, err := c.db.ListPinnedChirps(context.Background(), database.ListPinnedChirpsParams{UserID: user.ID, ViewerID: viewerID(&user)})
+	if err != nil {
+		log.Printf("Failed to list pinned chirps with err: %s", err)
+		respondWithError(w, 500, "Failed to pin Chirps")
+		return
+	}
+	c.respondWithPinnedChirps(w, 200, pinned, nil, &user)
+}
+
+// respondWithPinnedChirps responds with pinned followed by chirps, marking the
+// pinned ones. Rechirps can't be pinned, so building never drops one and the
+// first len(pinned) entries line up.
+func (c *apiConfig) respondWithPinnedChirps(w http.ResponseWriter, code int, pinned, chirps []database.Chirp, viewer *database.GetUserRow) {
+	out, err := c.buildChirps(withPinned(pinned, chirps), viewer)
+	if err != nil {
+		log.Printf("Failed to build chirps with err: %s", err)
+		respondWithError(w, 500, "Failed to get chirps")
+		return
+	}
+	isPinned := make(map[uuid.UUID]bool, len(pinned))
+	for _, chirp := range pinned {
+		isPinned[chirp.ID] = true
+	}
+	for i := range out {
+		out[i].Pinned = isPinned[out[i].ID]
+	}
+	respondWithJSON(w, code, out)
+}
+
+// withPinned puts the pinned chirps first, dropping them from the rest so no
+// chirp shows up twice in one response.
+func withPinned(pinned, chirps []database.Chirp) []database.Chirp {
+	out := make([]database.Chirp, 0, len(pinned)+len(chirps))
+	seen := make(map[uuid.UUID]bool, len(pinned))
+	for _, chirp := range pinned {
+		seen[chirp.ID] = true
+		out = append(out, chirp)
+	}
+	for _, chirp := range chirps {
+		if !seen[chirp.ID] {
+			out = append(out, chirp)
+		}
+	}
+	return out
+}
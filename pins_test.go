@@ -0,0 +1,25 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/cameronbarnes/go_chirpy/internal/database"
+	"github.com/google/uuid"
+)
+
+func TestWithPinnedDropsDuplicates(t *testing.T) {
+	a, b, c := uuid.New(), uuid.New(), uuid.New()
+	pinned := []database.Chirp{{ID: b}}
+	page := []database.Chirp{{ID: a}, {ID: b}, {ID: c}}
+
+	out := withPinned(pinned, page)
+	want := []uuid.UUID{b, a, c}
+	if len(out) != len(want) {
+		t.Fatalf("Expected %d chirps, got %d", len(want), len(out))
+	}
+	for i, id := range want {
+		if out[i].ID != id {
+			t.Errorf("Chirp %d: expected %s, got %s", i, id, out[i].ID)
+		}
+	}
+}
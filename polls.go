@@ -0,0 +1,183 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/cameronbarnes/go_chirpy/internal/chirptext"
+	"github.com/cameronbarnes/go_chirpy/internal/database"
+	"github.com/google/uuid"
+)
+
+const (
+	minPollOptions     = 2
+	maxPollOptions     = 4
+	maxPollLabelLength = 50
+	maxPollDuration    = 7 * 24 * time.Hour
+)
+
+type pollArgs struct {
+	Options  []string  `json:"options"`
+	ClosesAt time.Time `json:"closes_at"`
+}
+
+type pollOptionOut struct {
+	ID    uuid.UUID `json:"id"`
+	Label string    `json:"label"`
+	Votes *int64    `json:"votes,omitempty"`
+}
+
+// pollOut leaves the tallies out until the viewer has voted or the poll has
+// closed, so early results can't sway anyone.
+type pollOut struct {
+	ClosesAt   time.Time       `json:"closes_at"`
+	Closed     bool            `json:"closed"`
+	Options    []pollOptionOut `json:"options"`
+	TotalVotes *int64          `json:"total_votes,omitempty"`
+	VotedFor   *uuid.UUID      `json:"voted_for"`
+}
+
+// validatePoll checks the shape of a poll and returns the trimmed option
+// labels. The labels still go through the chirp pipeline with the body.
+// publishAt is when the chirp goes live, which is now unless it's scheduled.
+func validatePoll(arg pollArgs, publishAt time.Time) ([]string, error) {
+	if len(arg.Options) < minPollOptions || len(arg.Options) > maxPollOptions {
+		return nil, fmt.Errorf("Polls must have between %d and %d options", minPollOptions, maxPollOptions)
+	}
+	if !arg.ClosesAt.After(publishAt) {
+		return nil, errors.New("Poll must close after the chirp is published")
+	}
+	if arg.ClosesAt.Sub(publishAt) > maxPollDuration {
+		return nil, errors.New("Polls can run for at most 7 days")
+	}
+	labels := make([]string, len(arg.Options))
+	seen := map[string]bool{}
+	for i, option := range arg.Options {
+		option = strings.TrimSpace(option)
+		if option == "" {
+			return nil, errors.New("Poll options can't be empty")
+		}
+		if chirptext.Length(option) > maxPollLabelLength {
+			return nil, fmt.Errorf("Poll options can be at most %d characters", maxPollLabelLength)
+		}
+		if seen[strings.ToLower(option)] {
+			return nil, errors.New("Poll options must be unique")
+		}
+		seen[strings.ToLower(option)] = true
+		labels[i] = option
+	}
+	return labels, nil
+}
+
+func savePoll(q *database.Queries, chirp database.Chirp, closesAt time.Time, labels []string) error {
+	err := q.CreatePoll(context.Background(), database.CreatePollParams{ChirpID: chirp.ID, ClosesAt: closesAt})
+	if err != nil {
+		return err
+	}
+	for i, label := range labels {
+		err := q.AddPollOption(context.Background(), database.AddPollOptionParams{ChirpID: chirp.ID, Position: int32(i), Label: label})
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// loadPolls fetches the polls attached to any of ids, keyed by chirp ID.
+func (c *apiConfig) loadPolls(ids []uuid.UUID, viewer *database.GetUserRow) (map[uuid.UUID]*pollOut, error) {
+	options, err := c.db.GetPollOptionsForChirps(context.Background(), ids)
+	if err != nil {
+		return nil, err
+	}
+	out := map[uuid.UUID]*pollOut{}
+	if len(options) == 0 {
+		return out, nil
+	}
+	votedFor := map[uuid.UUID]uuid.UUID{}
+	if viewer != nil {
+		votes, err := c.db.GetPollVotesByUser(context.Background(), database.GetPollVotesByUserParams{UserID: viewer.ID, ChirpIds: ids})
+		if err != nil {
+			return nil, err
+		}
+		for _, vote := range votes {
+			votedFor[vote.ChirpID] = vote.OptionID
+		}
+	}
+	now := time.Now().UTC()
+	for _, option := range options {
+		poll, ok := out[option.ChirpID]
+		if !ok {
+			poll = &pollOut{ClosesAt: option.ClosesAt, Closed: !option.ClosesAt.After(now)}
+			if vote, voted := votedFor[option.ChirpID]; voted {
+				poll.VotedFor = &vote
+			}
+			if poll.Closed || poll.VotedFor != nil {
+				poll.TotalVotes = new(int64)
+			}
+			out[option.ChirpID] = poll
+		}
+		item := pollOptionOut{ID: option.ID, Label: option.Label}
+		if poll.TotalVotes != nil {
+			votes := option.VoteCount
+			item.Votes = &votes
+			*poll.TotalVotes += votes
+		}
+		poll.Options = append(poll.Options, item)
+	}
+	return out, nil
+}
+
+func (c *apiConfig) addPollVote(w http.ResponseWriter, r *http.Request, user database.GetUserRow) {
+	type voteArgs struct {
+		OptionID uuid.UUID `json:"option_id"`
+	}
+	id, err := uuid.Parse(r.PathValue("chirpID"))
+	if err != nil {
+		respondWithError(w, 400, "UUID provided is not valid")
+		return
+	}
+	arg, err := handleParse[voteArgs](w, r)
+	if err != nil {
+		return
+	}
+	chirp, err := c.getOriginalChirp(id, &user)
+	if err != nil {
+		respondWithError(w, 404, "Chirp Not Found")
+		return
+	}
+	poll, err := c.db.GetPoll(context.Background(), chirp.ID)
+	if errors.Is(err, sql.ErrNoRows) {
+		respondWithError(w, 404, "Chirp does not have a poll")
+		return
+	}
+	if err != nil {
+		log.Printf("Failed to get poll with err: %s", err)
+		respondWithError(w, 500, "Failed to vote")
+		return
+	}
+	if !poll.ClosesAt.After(time.Now().UTC()) {
+		respondWithError(w, 403, "Poll has closed")
+		return
+	}
+	err = c.db.AddPollVote(context.Background(), database.AddPollVoteParams{ChirpID: chirp.ID, UserID: user.ID, OptionID: arg.OptionID})
+	if isUniqueViolation(err) {
+		respondWithError(w, 409, "You have already voted in this poll")
+		return
+	}
+	if isForeignKeyViolation(err) {
+		respondWithError(w, 400, "Option is not part of this poll")
+		return
+	}
+	if err != nil {
+		log.Printf("Failed to vote with err: %s", err)
+		respondWithError(w, 500, "Failed to vote")
+		return
+	}
+	c.respondWithChirp(w, 201, chirp, &user)
+}
@@ -0,0 +1,49 @@
+package main
+
+import (
+	"slices"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestValidatePoll(t *testing.T) {
+	now := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+	day := now.Add(24 * time.Hour)
+	tests := []struct {
+		name     string
+		options  []string
+		closesAt time.Time
+		want     []string
+		wantErr  bool
+	}{
+		{name: "valid", options: []string{" Yes ", "No"}, closesAt: day, want: []string{"Yes", "No"}},
+		{name: "most options", options: []string{"a", "b", "c", "d"}, closesAt: day, want: []string{"a", "b", "c", "d"}},
+		{name: "longest label", options: []string{strings.Repeat("é", maxPollLabelLength), "b"}, closesAt: day, want: []string{strings.Repeat("é", maxPollLabelLength), "b"}},
+		{name: "longest duration", options: []string{"a", "b"}, closesAt: now.Add(maxPollDuration), want: []string{"a", "b"}},
+		{name: "too few options", options: []string{"a"}, closesAt: day, wantErr: true},
+		{name: "too many options", options: []string{"a", "b", "c", "d", "e"}, closesAt: day, wantErr: true},
+		{name: "label too long", options: []string{strings.Repeat("a", maxPollLabelLength+1), "b"}, closesAt: day, wantErr: true},
+		{name: "blank label", options: []string{"a", "   "}, closesAt: day, wantErr: true},
+		{name: "duplicate labels", options: []string{"Yes", " yes"}, closesAt: day, wantErr: true},
+		{name: "closes at publish", options: []string{"a", "b"}, closesAt: now, wantErr: true},
+		{name: "closes before publish", options: []string{"a", "b"}, closesAt: now.Add(-time.Hour), wantErr: true},
+		{name: "runs too long", options: []string{"a", "b"}, closesAt: now.Add(maxPollDuration + time.Second), wantErr: true},
+	}
+	for _, test := range tests {
+		labels, err := validatePoll(pollArgs{Options: test.options, ClosesAt: test.closesAt}, now)
+		if test.wantErr {
+			if err == nil {
+				t.Errorf("%s: expected an error, got %q", test.name, labels)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("%s: unexpected error: %s", test.name, err)
+			continue
+		}
+		if !slices.Equal(labels, test.want) {
+			t.Errorf("%s: expected %q, got %q", test.name, test.want, labels)
+		}
+	}
+}
@@ -0,0 +1,198 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"os"
+	"strings"
+
+	"github.com/cameronbarnes/go_chirpy/internal/chirptext"
+	"github.com/cameronbarnes/go_chirpy/internal/database"
+)
+
+const defaultChirpProcessors = "length,profanity,links,spam"
+
+// pendingChirp is a chirp on its way into the database. Processors can
+// rewrite the text, or annotate it for the ones that run after them.
+type pendingChirp struct {
+	Author      database.GetUserRow
+	Body        string
+	PollOptions []string
+	Links       []string
+	SpamScore   int
+	Flagged     bool
+}
+
+// ChirpProcessor is one step in checking a new or edited chirp. It returns an
+// invalidChirpError to refuse the chirp, and any other error when it couldn't
+// do its job.
+type ChirpProcessor interface {
+	Process(ctx context.Context, chirp *pendingChirp) error
+}
+
+type chirpPipeline []ChirpProcessor
+
+func (p chirpPipeline) Run(ctx context.Context, chirp *pendingChirp) error {
+	for _, processor := range p {
+		if err := processor.Process(ctx, chirp); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// chirpPipelineFromEnv builds the pipeline named by CHIRP_PROCESSORS, a comma
+// separated list run in the order given.
+func chirpPipelineFromEnv(db *database.Queries) (chirpPipeline, error) {
+	available := map[string]func() ChirpProcessor{
+		"length": func() ChirpProcessor {
+			return lengthProcessor{limit: intFromEnv("CHIRP_MAX_LENGTH", 140), redLimit: intFromEnv("CHIRP_MAX_LENGTH_RED", 280)}
+		},
+		"profanity": func() ChirpProcessor {
+			return profanityProcessor{db: db}
+		},
+		"links": func() ChirpProcessor {
+			return linkProcessor{maxLinks: intFromEnv("CHIRP_MAX_LINKS", 5)}
+		},
+		"spam": func() ChirpProcessor {
+			return spamProcessor{threshold: intFromEnv("CHIRP_SPAM_THRESHOLD", 3)}
+		},
+	}
+	names := os.Getenv("CHIRP_PROCESSORS")
+	if names == "" {
+		names = defaultChirpProcessors
+	}
+	pipeline := chirpPipeline{}
+	for name := range strings.SplitSeq(names, ",") {
+		name = strings.TrimSpace(name)
+		build, ok := available[name]
+		if !ok {
+			return nil, fmt.Errorf("Unknown chirp processor: %q", name)
+		}
+		pipeline = append(pipeline, build())
+	}
+	return pipeline, nil
+}
+
+type invalidChirpError string
+
+func (e invalidChirpError) Error() string {
+	return string(e)
+}
+
+const errChirpRejected = invalidChirpError("Chirp contains language that isn't allowed")
+
+type chirpLengthError struct {
+	Limit  int
+	Length int
+}
+
+func (e chirpLengthError) Error() string {
+	return fmt.Sprintf("Chirp is too long: %d characters, the limit is %d", e.Length, e.Limit)
+}
+
+// respondWithChirpError reports an error from the pipeline. Length errors
+// also carry the numbers so clients can show how much needs cutting.
+func respondWithChirpError(w http.ResponseWriter, err error) {
+	type lengthErr struct {
+		Error  string `json:"error"`
+		Limit  int    `json:"limit"`
+		Length int    `json:"length"`
+	}
+	var tooLong chirpLengthError
+	if errors.As(err, &tooLong) {
+		respondWithJSON(w, 400, lengthErr{Error: tooLong.Error(), Limit: tooLong.Limit, Length: tooLong.Length})
+		return
+	}
+	var invalid invalidChirpError
+	if errors.As(err, &invalid) {
+		respondWithError(w, 400, invalid.Error())
+		return
+	}
+	log.Printf("Failed to process chirp with err: %s", err)
+	respondWithError(w, 500, "Failed to process Chirp")
+}
+
+// lengthProcessor limits the body length in characters, with a separate limit
+// for Chirpy Red members.
+type lengthProcessor struct {
+	limit    int
+	redLimit int
+}
+
+func (p lengthProcessor) Process(_ context.Context, chirp *pendingChirp) error {
+	limit := p.limit
+	if chirp.Author.IsChirpyRed {
+		limit = p.redLimit
+	}
+	if length := chirptext.Length(chirp.Body); length > limit {
+		return chirpLengthError{Limit: limit, Length: length}
+	}
+	return nil
+}
+
+// profanityProcessor applies the word filter to the body and poll options.
+// The words are read on every chirp rather than cached so edits through the
+// admin API apply to every server at once.
+type profanityProcessor struct {
+	db *database.Queries
+}
+
+func (p profanityProcessor) Process(ctx context.Context, chirp *pendingChirp) error {
+	words, err := p.db.ListFilterWords(ctx)
+	if err != nil {
+		return err
+	}
+	rules := make(map[string]chirptext.FilterAction, len(words))
+	for _, word := range words {
+		// Words saved before matching keys existed were only lower-cased.
+		rules[chirptext.NormalizeFilterWord(word.Word)] = chirptext.FilterAction(word.Action)
+	}
+	filter := func(text string) (string, error) {
+		filtered := chirptext.Filter(text, rules)
+		if filtered.Rejected {
+			return "", errChirpRejected
+		}
+		chirp.Flagged = chirp.Flagged || filtered.Flagged
+		return filtered.Body, nil
+	}
+	if chirp.Body, err = filter(chirp.Body); err != nil {
+		return err
+	}
+	for i, option := range chirp.PollOptions {
+		if chirp.PollOptions[i], err = filter(option); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// linkProcessor records the links in the body and caps how many there can be.
+type linkProcessor struct {
+	maxLinks int
+}
+
+func (p linkProcessor) Process(_ context.Context, chirp *pendingChirp) error {
+	chirp.Links = chirptext.Links(chirp.Body)
+	if len(chirp.Links) > p.maxLinks {
+		return invalidChirpError(fmt.Sprintf("Chirps can have at most %d links", p.maxLinks))
+	}
+	return nil
+}
+
+// spamProcessor flags chirps that score at or above the threshold so a
+// moderator can take a look. It doesn't reject anything on its own.
+type spamProcessor struct {
+	threshold int
+}
+
+func (p spamProcessor) Process(_ context.Context, chirp *pendingChirp) error {
+	chirp.SpamScore = chirptext.SpamScore(chirp.Body)
+	if chirp.SpamScore >= p.threshold {
+		chirp.Flagged = true
+	}
+	return nil
+}
@@ -0,0 +1,135 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/cameronbarnes/go_chirpy/internal/database"
+)
+
+func TestLengthProcessor(t *testing.T) {
+	p := lengthProcessor{limit: 5, redLimit: 8}
+	tests := []struct {
+		name      string
+		body      string
+		red       bool
+		wantLimit int
+		wantLen   int
+	}{
+		{name: "at the limit", body: "abcde"},
+		{name: "over the limit", body: "abcdef", wantLimit: 5, wantLen: 6},
+		{name: "graphemes count once", body: "👨‍👩‍👧éabc"},
+		{name: "red within its limit", body: "abcdefgh", red: true},
+		{name: "red over its limit", body: "abcdefghi", red: true, wantLimit: 8, wantLen: 9},
+	}
+	for _, test := range tests {
+		chirp := pendingChirp{Author: database.GetUserRow{IsChirpyRed: test.red}, Body: test.body}
+		err := p.Process(context.Background(), &chirp)
+		if test.wantLimit == 0 {
+			if err != nil {
+				t.Errorf("%s: unexpected error: %s", test.name, err)
+			}
+			continue
+		}
+		var tooLong chirpLengthError
+		if !errors.As(err, &tooLong) {
+			t.Errorf("%s: expected a length error, got %v", test.name, err)
+			continue
+		}
+		if tooLong.Limit != test.wantLimit || tooLong.Length != test.wantLen {
+			t.Errorf("%s: expected %d/%d, got %d/%d", test.name, test.wantLen, test.wantLimit, tooLong.Length, tooLong.Limit)
+		}
+	}
+}
+
+func TestRespondWithChirpError(t *testing.T) {
+	tests := []struct {
+		name       string
+		err        error
+		wantCode   int
+		wantLimit  int
+		wantLength int
+	}{
+		{name: "too long", err: chirpLengthError{Limit: 280, Length: 300}, wantCode: 400, wantLimit: 280, wantLength: 300},
+		{name: "rejected", err: errChirpRejected, wantCode: 400},
+		{name: "other", err: errors.New("database is down"), wantCode: 500},
+	}
+	for _, test := range tests {
+		w := httptest.NewRecorder()
+		respondWithChirpError(w, test.err)
+		if w.Code != test.wantCode {
+			t.Errorf("%s: expected %d, got %d", test.name, test.wantCode, w.Code)
+		}
+		var body map[string]any
+		if err := json.Unmarshal(w.Body.Bytes(), &body); err != nil {
+			t.Errorf("%s: invalid body %q: %s", test.name, w.Body.String(), err)
+			continue
+		}
+		if msg, _ := body["error"].(string); msg == "" || strings.Contains(msg, "database") {
+			t.Errorf("%s: unexpected error message %q", test.name, msg)
+		}
+		_, hasLimit := body["limit"]
+		_, hasLength := body["length"]
+		if hasLimit != (test.wantLimit != 0) || hasLength != (test.wantLength != 0) {
+			t.Errorf("%s: unexpected fields in %v", test.name, body)
+			continue
+		}
+		if test.wantLimit != 0 && (body["limit"] != float64(test.wantLimit) || body["length"] != float64(test.wantLength)) {
+			t.Errorf("%s: expected limit %d and length %d, got %v", test.name, test.wantLimit, test.wantLength, body)
+		}
+	}
+}
+
+func TestChirpPipelineFromEnv(t *testing.T) {
+	tests := []struct {
+		env     string
+		want    []string
+		wantErr bool
+	}{
+		{env: "", want: []string{"main.lengthProcessor", "main.profanityProcessor", "main.linkProcessor", "main.spamProcessor"}},
+		{env: "spam,length", want: []string{"main.spamProcessor", "main.lengthProcessor"}},
+		{env: " links , length ", want: []string{"main.linkProcessor", "main.lengthProcessor"}},
+		{env: "length,emoji", wantErr: true},
+		{env: "length,,spam", wantErr: true},
+		{env: "Length", wantErr: true},
+	}
+	for _, test := range tests {
+		t.Setenv("CHIRP_PROCESSORS", test.env)
+		pipeline, err := chirpPipelineFromEnv(nil)
+		if test.wantErr {
+			if err == nil {
+				t.Errorf("%q: expected an error", test.env)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("%q: unexpected error: %s", test.env, err)
+			continue
+		}
+		got := make([]string, len(pipeline))
+		for i, processor := range pipeline {
+			got[i] = fmt.Sprintf("%T", processor)
+		}
+		if strings.Join(got, ",") != strings.Join(test.want, ",") {
+			t.Errorf("%q: expected %v, got %v", test.env, test.want, got)
+		}
+	}
+}
+
+func TestChirpPipelineFromEnvReadsLimits(t *testing.T) {
+	t.Setenv("CHIRP_PROCESSORS", "length")
+	t.Setenv("CHIRP_MAX_LENGTH", "200")
+	t.Setenv("CHIRP_MAX_LENGTH_RED", "500")
+	pipeline, err := chirpPipelineFromEnv(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := pipeline[0]; got != (lengthProcessor{limit: 200, redLimit: 500}) {
+		t.Errorf("Unexpected processor: %+v", got)
+	}
+}
@@ -0,0 +1,86 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"log"
+	"net/http"
+
+	"github.com/cameronbarnes/go_chirpy/internal/database"
+	"github.com/google/uuid"
+)
+
+// getOriginalChirp loads a chirp that viewer wants to act on, such as to like
+// or rechirp it. Rechirps have no body of their own, so they resolve to the
+// chirp they point at.
+func (c *apiConfig) getOriginalChirp(id uuid.UUID, viewer *database.GetUserRow) (database.Chirp, error) {
+	chirp, err := c.db.GetChirp(context.Background(), id)
+	if err != nil {
+		return chirp, err
+	}
+	if chirp.RechirpOf.Valid {
+		chirp, err = c.db.GetChirp(context.Background(), chirp.RechirpOf.UUID)
+		if err != nil {
+			return chirp, err
+		}
+	}
+	if chirpRemoved(chirp) {
+		return chirp, sql.ErrNoRows
+	}
+	visible, err := c.chirpVisibleTo(chirp, viewer)
+	if err != nil {
+		return chirp, err
+	}
+	if !visible {
+		return chirp, sql.ErrNoRows
+	}
+	return chirp, nil
+}
+
+func (c *apiConfig) addRechirp(w http.ResponseWriter, r *http.Request, user database.GetUserRow) {
+	id, err := uuid.Parse(r.PathValue("chirpID"))
+	if err != nil {
+		respondWithError(w, 400, "UUID provided is not valid")
+		return
+	}
+	original, err := c.getOriginalChirp(id, &user)
+	if err != nil {
+		respondWithError(w, 404, "Chirp Not Found")
+		return
+	}
+	if !chirpPublic(original) {
+		respondWithError(w, 400, "Only public Chirps can be rechirped")
+		return
+	}
+	rechirp, err := c.db.AddRechirp(context.Background(), database.AddRechirpParams{UserID: user.ID, RechirpOf: uuid.NullUUID{UUID: original.ID, Valid: true}})
+	if isUniqueViolation(err) {
+		respondWithError(w, 409, "Chirp has already been rechirped")
+		return
+	}
+	if err != nil {
+		log.Printf("Failed to rechirp with err: %s", err)
+		respondWithError(w, 500, "Failed to rechirp")
+		return
+	}
+	c.respondWithChirp(w, 201, rechirp, &user)
+}
+
+func (c *apiConfig) deleteRechirp(w http.ResponseWriter, r *http.Request, user database.GetUserRow) {
+	id, err := uuid.Parse(r.PathValue("chirpID"))
+	if err != nil {
+		respondWithError(w, 400, "UUID provided is not valid")
+		return
+	}
+	_, err = c.db.DeleteRechirp(context.Background(), database.DeleteRechirpParams{UserID: user.ID, RechirpOf: uuid.NullUUID{UUID: id, Valid: true}})
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			respondWithError(w, 404, "Rechirp Not Found")
+		} else {
+			log.Printf("Failed to undo rechirp with err: %s", err)
+			respondWithError(w, 500, "Failed to undo rechirp")
+		}
+		return
+	}
+	w.WriteHeader(204)
+}
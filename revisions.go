@@ -0,0 +1,141 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/cameronbarnes/go_chirpy/internal/database"
+	"github.com/google/uuid"
+)
+
+var errChirpRemoved = errors.New("Chirp has been deleted")
+
+func (c *apiConfig) editChirp(w http.ResponseWriter, r *http.Request, user database.GetUserRow) {
+	type editArgs struct {
+		Body string `json:"body"`
+	}
+	chirp, ok := c.getOwnedChirp(w, r, user)
+	if !ok {
+		return
+	}
+	if chirp.RechirpOf.Valid {
+		respondWithError(w, 400, "Rechirps can't be edited")
+		return
+	}
+	if time.Since(chirp.CreatedAt) > c.editWindow {
+		respondWithError(w, 403, "Chirp can no longer be edited")
+		return
+	}
+	arg, err := handleParse[editArgs](w, r)
+	if err != nil {
+		respondWithError(w, 400, "Invalid Request")
+		return
+	}
+	pending := pendingChirp{Author: user, Body: arg.Body}
+	if err := c.processors.Run(context.Background(), &pending); err != nil {
+		respondWithChirpError(w, err)
+		return
+	}
+	if pending.Body == chirp.Body {
+		c.respondWithChirp(w, 200, chirp, &user)
+		return
+	}
+	var updated database.Chirp
+	err = c.withTx(func(q *database.Queries) error {
+		// Lock the chirp and keep the body it has now, so an edit that
+		// lands in between still leaves its version in the history.
+		current, err := q.GetChirpForUpdate(context.Background(), chirp.ID)
+		if err != nil {
+			return err
+		}
+		if chirpRemoved(current) {
+			return errChirpRemoved
+		}
+		updated = current
+		if current.Body == pending.Body {
+			return nil
+		}
+		err = q.AddChirpRevision(context.Background(), database.AddChirpRevisionParams{ChirpID: chirp.ID, Body: current.Body})
+		if err != nil {
+			return err
+		}
+		updated, err = q.UpdateChirpBody(context.Background(), database.UpdateChirpBodyParams{ID: chirp.ID, Body: pending.Body, Flagged: pending.Flagged, Entities: chirpEntities(pending.Body)})
+		if err != nil {
+			return err
+		}
+		if err := clearChirpEntities(q, chirp.ID); err != nil {
+			return err
+		}
+		return saveChirpEntities(q, updated)
+	})
+	if errors.Is(err, sql.ErrNoRows) {
+		respondWithError(w, 404, "Chirp Not Found")
+		return
+	}
+	if errors.Is(err, errChirpRemoved) {
+		respondWithError(w, 410, "Chirp has been deleted")
+		return
+	}
+	if err != nil {
+		log.Printf("Failed to edit chirp with err: %s", err)
+		respondWithError(w, 500, "Failed to edit Chirp")
+		return
+	}
+	c.respondWithChirp(w, 200, updated, &user)
+}
+
+func (c *apiConfig) getChirpRevisions(w http.ResponseWriter, r *http.Request, viewer *database.GetUserRow) {
+	id, err := uuid.Parse(r.PathValue("chirpID"))
+	if err != nil {
+		respondWithError(w, 400, "UUID provided is not valid")
+		return
+	}
+	chirp, err := c.db.GetChirp(context.Background(), id)
+	if err != nil {
+		respondWithError(w, 404, "Chirp Not Found")
+		return
+	}
+	visible, err := c.chirpVisibleTo(chirp, viewer)
+	if err != nil {
+		log.Printf("Failed to check chirp visibility with err: %s", err)
+		respondWithError(w, 500, "Failed to get revisions")
+		return
+	}
+	if !visible {
+		respondWithError(w, 404, "Chirp Not Found")
+		return
+	}
+	if chirpRemoved(chirp) {
+		respondWithError(w, 410, "Chirp has been deleted")
+		return
+	}
+	revisions, err := c.db.ListChirpRevisions(context.Background(), chirp.ID)
+	if err != nil {
+		log.Printf("Failed to get revisions with err: %s", err)
+		respondWithError(w, 500, "Failed to get revisions")
+		return
+	}
+	respondWithJSON(w, 200, revisionsOut(revisions, chirpDisplay(chirp, viewer)))
+}
+
+type revisionOut struct {
+	database.ChirpRevision
+	Display string `json:"display"`
+}
+
+// revisionsOut shows past bodies the way the chirp itself is shown, since
+// its labels cover every version of it.
+func revisionsOut(revisions []database.ChirpRevision, display string) []revisionOut {
+	out := make([]revisionOut, len(revisions))
+	for i, revision := range revisions {
+		if display == displayCollapsed {
+			revision.Body = ""
+		}
+		out[i] = revisionOut{ChirpRevision: revision, Display: display}
+	}
+	return out
+}
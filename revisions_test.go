@@ -0,0 +1,26 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/cameronbarnes/go_chirpy/internal/database"
+)
+
+func TestRevisionsOutFollowsChirpDisplay(t *testing.T) {
+	revisions := []database.ChirpRevision{{Body: "first draft"}, {Body: "second draft"}}
+	for _, display := range []string{displayFull, displayBlur} {
+		for _, revision := range revisionsOut(revisions, display) {
+			if revision.Body == "" || revision.Display != display {
+				t.Errorf("%s: unexpected revision %+v", display, revision)
+			}
+		}
+	}
+	for _, revision := range revisionsOut(revisions, displayCollapsed) {
+		if revision.Body != "" || revision.Display != displayCollapsed {
+			t.Errorf("Collapsed revisions should have no body, got %+v", revision)
+		}
+	}
+	if out := revisionsOut(nil, displayFull); out == nil || len(out) != 0 {
+		t.Errorf("Expected an empty list, got %#v", out)
+	}
+}
@@ -0,0 +1,27 @@
+package main
+
+import (
+	"context"
+	"log"
+	"time"
+)
+
+// publishScheduledChirps runs until the process exits, publishing chirps whose
+// publish_at has passed. PublishDueChirps locks the rows it claims, so several
+// instances can run this at once without publishing a chirp twice.
+func (c *apiConfig) publishScheduledChirps(interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		published, err := c.db.PublishDueChirps(context.Background())
+		if err != nil {
+			log.Printf("Failed to publish scheduled chirps with err: %s", err)
+		} else if len(published) > 0 {
+			log.Printf("Published %d scheduled chirps", len(published))
+		}
+		for _, chirp := range published {
+			c.recordTrends(chirp)
+		}
+		<-ticker.C
+	}
+}
@@ -0,0 +1,51 @@
+package main
+
+import (
+	"context"
+	"log"
+	"net/http"
+	"strings"
+
+	"github.com/cameronbarnes/go_chirpy/internal/database"
+	"github.com/google/uuid"
+)
+
+func (c *apiConfig) searchChirps(w http.ResponseWriter, r *http.Request, viewer *database.GetUserRow) {
+	query := strings.TrimSpace(r.URL.Query().Get("q"))
+	if query == "" {
+		respondWithError(w, 400, "Search query is required")
+		return
+	}
+	// Results are ordered by rank, which a created_at cursor can't resume
+	// from, so say so rather than quietly serving the first page again.
+	if r.URL.Query().Has("cursor") {
+		respondWithError(w, 400, "Search results can't be paged with a cursor, narrow them with since and until instead")
+		return
+	}
+	p, err := parsePage(r)
+	if err != nil {
+		respondWithError(w, 400, err.Error())
+		return
+	}
+	since, until, err := parseTimeRange(r)
+	if err != nil {
+		respondWithError(w, 400, err.Error())
+		return
+	}
+	params := database.SearchChirpsParams{Query: query, Since: since, Until: until, ViewerID: viewerID(viewer), PageSize: p.Size}
+	if author_id := r.URL.Query().Get("author_id"); author_id != "" {
+		id, err := uuid.Parse(author_id)
+		if err != nil {
+			respondWithError(w, 400, "Failed to parse UserID UUID")
+			return
+		}
+		params.AuthorID = uuid.NullUUID{UUID: id, Valid: true}
+	}
+	chirps, err := c.db.SearchChirps(context.Background(), params)
+	if err != nil {
+		log.Printf("Failed to search chirps with err: %s", err)
+		respondWithError(w, 500, "Failed to search chirps")
+		return
+	}
+	c.respondWithChirps(w, 200, chirps, viewer)
+}
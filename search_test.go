@@ -0,0 +1,19 @@
+package main
+
+import (
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+func TestSearchRejectsCursor(t *testing.T) {
+	c := &apiConfig{}
+	w := httptest.NewRecorder()
+	r := httptest.NewRequest("GET", "/api/chirps/search?q=go&cursor="+encodeCursor(time.Now(), uuid.New()), nil)
+	c.searchChirps(w, r, nil)
+	if w.Code != 400 {
+		t.Errorf("Expected 400, got %d", w.Code)
+	}
+}
@@ -0,0 +1,72 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/cameronbarnes/go_chirpy/internal/database"
+	"github.com/google/uuid"
+)
+
+func (c *apiConfig) restoreChirp(w http.ResponseWriter, r *http.Request, user database.GetUserRow) {
+	id, err := uuid.Parse(r.PathValue("chirpID"))
+	if err != nil {
+		respondWithError(w, 400, "UUID provided is not valid")
+		return
+	}
+	chirp, err := c.db.RestoreChirp(context.Background(), database.RestoreChirpParams{ID: id, UserID: user.ID, DeletedAfter: time.Now().UTC().Add(-c.restoreWindow)})
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			respondWithError(w, 404, "No restorable Chirp found")
+		} else {
+			log.Printf("Failed to restore chirp with err: %s", err)
+			respondWithError(w, 500, "Failed to restore Chirp")
+		}
+		return
+	}
+	c.respondWithChirp(w, 200, chirp, &user)
+}
+
+// purgeDeletedChirps runs until the process exits, permanently removing
+// chirps once their restore window has passed.
+func (c *apiConfig) purgeDeletedChirps(interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		if err := c.purgeExpiredChirps(); err != nil {
+			log.Printf("Failed to purge deleted chirps with err: %s", err)
+		}
+		<-ticker.C
+	}
+}
+
+func (c *apiConfig) purgeExpiredChirps() error {
+	ids, err := c.db.ListPurgeableChirps(context.Background(), time.Now().UTC().Add(-c.restoreWindow))
+	if err != nil {
+		return err
+	}
+	for _, id := range ids {
+		err := c.withTx(func(q *database.Queries) error {
+			hasReplies, err := q.ChirpHasReplies(context.Background(), id)
+			if err != nil {
+				return err
+			}
+			if !hasReplies {
+				return q.PurgeChirp(context.Background(), id)
+			}
+			// Keep the row so replies still have a parent to hang off in threads.
+			if err := q.TombstoneChirp(context.Background(), id); err != nil {
+				return err
+			}
+			return clearChirpEntities(q, id)
+		})
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
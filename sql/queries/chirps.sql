@@ -1,22 +1,187 @@
 -- name: AddChirp :one
-INSERT INTO chirps (id, body, user_id)
-VALUES (gen_random_uuid(), $1, $2)
+INSERT INTO chirps (id, created_at, updated_at, body, user_id, parent_id, quote_of, publish_at, published, flagged, visibility, content_warning, sensitive, entities)
+VALUES (
+    gen_random_uuid(),
+    COALESCE(sqlc.narg('publish_at')::timestamp, NOW()),
+    COALESCE(sqlc.narg('publish_at')::timestamp, NOW()),
+    sqlc.arg('body'),
+    sqlc.arg('user_id'),
+    sqlc.narg('parent_id'),
+    sqlc.narg('quote_of'),
+    sqlc.narg('publish_at')::timestamp,
+    sqlc.narg('publish_at')::timestamp IS NULL,
+    sqlc.arg('flagged'),
+    sqlc.arg('visibility'),
+    sqlc.arg('content_warning'),
+    sqlc.arg('sensitive'),
+    sqlc.arg('entities')
+)
 RETURNING *;
 
--- name: AllChirps :many
+-- name: GetChirp :one
 SELECT * FROM chirps
-ORDER BY created_at ASC;
+WHERE id = $1;
 
--- name: AllChirpsFromUser :many
+-- name: GetChirpForUpdate :one
 SELECT * FROM chirps
-WHERE user_id = $1
-ORDER BY created_at ASC;
+WHERE id = $1
+FOR UPDATE;
 
--- name: GetChirp :one
-SELECT * FROM chirps
-WHERE id = $1;
+-- name: GetChirpAncestors :many
+WITH RECURSIVE ancestors AS (
+    SELECT parent.id, parent.parent_id, 1 AS depth FROM chirps parent
+    WHERE parent.id = (SELECT chirps.parent_id FROM chirps WHERE chirps.id = $1)
+    UNION ALL
+    SELECT parent.id, parent.parent_id, ancestors.depth + 1 FROM chirps parent
+    INNER JOIN ancestors ON parent.id = ancestors.parent_id
+)
+SELECT chirps.* FROM chirps
+INNER JOIN ancestors ON chirps.id = ancestors.id
+ORDER BY ancestors.depth DESC;
+
+-- name: GetChirpDescendants :many
+WITH RECURSIVE descendants AS (
+    SELECT reply.id FROM chirps reply
+    WHERE reply.parent_id = sqlc.arg('id')::uuid
+    UNION ALL
+    SELECT reply.id FROM chirps reply
+    INNER JOIN descendants ON reply.parent_id = descendants.id
+)
+SELECT chirps.* FROM chirps
+INNER JOIN descendants ON chirps.id = descendants.id
+ORDER BY chirps.created_at ASC, chirps.id ASC;
+
+-- name: ChirpHasReplies :one
+SELECT EXISTS(SELECT 1 FROM chirps WHERE parent_id = sqlc.arg('id')::uuid);
 
 -- name: DeleteChirp :one
+UPDATE chirps
+SET deleted_at = NOW(), updated_at = NOW()
+WHERE id = $1 AND user_id = $2 AND deleted_at IS NULL
+RETURNING id;
+
+-- name: TombstoneChirp :exec
+UPDATE chirps
+SET body = '', entities = '[]', is_tombstone = true, updated_at = NOW()
+WHERE id = $1;
+
+-- name: ListChirps :many
+SELECT * FROM chirps
+WHERE (sqlc.narg('author_id')::uuid IS NULL OR user_id = sqlc.narg('author_id')::uuid)
+AND (sqlc.narg('since')::timestamp IS NULL OR created_at >= sqlc.narg('since')::timestamp)
+AND (sqlc.narg('until')::timestamp IS NULL OR created_at < sqlc.narg('until')::timestamp)
+AND (sqlc.narg('after_created_at')::timestamp IS NULL OR (created_at, id) > (sqlc.narg('after_created_at')::timestamp, sqlc.narg('after_id')::uuid))
+AND NOT is_tombstone AND deleted_at IS NULL
+AND chirp_visible_to(chirps.id, chirps.user_id, chirps.published, chirps.hidden_at, chirps.visibility, sqlc.narg('viewer_id')::uuid)
+ORDER BY created_at ASC, id ASC
+LIMIT sqlc.arg('page_size');
+
+-- name: ListChirpsDesc :many
+SELECT * FROM chirps
+WHERE (sqlc.narg('author_id')::uuid IS NULL OR user_id = sqlc.narg('author_id')::uuid)
+AND (sqlc.narg('since')::timestamp IS NULL OR created_at >= sqlc.narg('since')::timestamp)
+AND (sqlc.narg('until')::timestamp IS NULL OR created_at < sqlc.narg('until')::timestamp)
+AND (sqlc.narg('after_created_at')::timestamp IS NULL OR (created_at, id) < (sqlc.narg('after_created_at')::timestamp, sqlc.narg('after_id')::uuid))
+AND NOT is_tombstone AND deleted_at IS NULL
+AND chirp_visible_to(chirps.id, chirps.user_id, chirps.published, chirps.hidden_at, chirps.visibility, sqlc.narg('viewer_id')::uuid)
+ORDER BY created_at DESC, id DESC
+LIMIT sqlc.arg('page_size');
+
+-- name: SearchChirps :many
+SELECT * FROM chirps
+WHERE search_vector @@ websearch_to_tsquery('english', sqlc.arg('query')::text)
+AND (sqlc.narg('author_id')::uuid IS NULL OR user_id = sqlc.narg('author_id')::uuid)
+AND (sqlc.narg('since')::timestamp IS NULL OR created_at >= sqlc.narg('since')::timestamp)
+AND (sqlc.narg('until')::timestamp IS NULL OR created_at < sqlc.narg('until')::timestamp)
+AND NOT is_tombstone AND deleted_at IS NULL
+AND chirp_visible_to(chirps.id, chirps.user_id, chirps.published, chirps.hidden_at, chirps.visibility, sqlc.narg('viewer_id')::uuid)
+ORDER BY ts_rank(search_vector, websearch_to_tsquery('english', sqlc.arg('query')::text)) DESC, created_at DESC
+LIMIT sqlc.arg('page_size');
+
+-- name: AddRechirp :one
+INSERT INTO chirps (id, body, user_id, rechirp_of)
+VALUES (gen_random_uuid(), '', $1, $2)
+RETURNING *;
+
+-- name: DeleteRechirp :one
 DELETE FROM chirps
-WHERE id = $1 AND user_id = $2
+WHERE user_id = $1 AND rechirp_of = $2
 RETURNING id;
+
+-- name: CountRechirpsForChirps :many
+SELECT rechirp_of, COUNT(*) AS rechirp_count FROM chirps
+WHERE rechirp_of = ANY(sqlc.arg('chirp_ids')::uuid[]) AND deleted_at IS NULL
+GROUP BY rechirp_of;
+
+-- name: GetChirpsByIDs :many
+SELECT * FROM chirps
+WHERE id = ANY(sqlc.arg('ids')::uuid[]);
+
+-- name: UpdateChirpBody :one
+UPDATE chirps
+SET body = $1, flagged = $2, entities = $3, updated_at = NOW()
+WHERE id = $4
+RETURNING *;
+
+-- name: RestoreChirp :one
+UPDATE chirps
+SET deleted_at = NULL, updated_at = NOW()
+WHERE id = sqlc.arg('id') AND user_id = sqlc.arg('user_id') AND deleted_at > sqlc.arg('deleted_after')::timestamp
+RETURNING *;
+
+-- name: ListPurgeableChirps :many
+SELECT id FROM chirps
+WHERE deleted_at < sqlc.arg('deleted_before')::timestamp AND NOT is_tombstone
+LIMIT 500;
+
+-- name: PurgeChirp :exec
+DELETE FROM chirps
+WHERE id = $1;
+
+-- name: PublishDueChirps :many
+UPDATE chirps
+SET published = true, updated_at = NOW()
+WHERE NOT published AND id IN (
+    SELECT due.id FROM chirps due
+    WHERE NOT due.published AND due.publish_at <= NOW()
+    ORDER BY due.publish_at
+    LIMIT 100
+    FOR UPDATE SKIP LOCKED
+)
+RETURNING *;
+
+-- name: HideChirp :exec
+UPDATE chirps
+SET hidden_at = COALESCE(hidden_at, NOW())
+WHERE id = $1;
+
+-- name: UnhideChirp :exec
+UPDATE chirps
+SET hidden_at = NULL
+WHERE id = $1;
+
+-- name: ClearChirpFlag :exec
+UPDATE chirps
+SET flagged = false
+WHERE id = $1;
+
+-- name: FilterVisibleChirpIDs :many
+SELECT chirps.id FROM chirps
+WHERE chirps.id = ANY(sqlc.arg('ids')::uuid[])
+AND chirp_visible_to(chirps.id, chirps.user_id, chirps.published, chirps.hidden_at, chirps.visibility, sqlc.arg('viewer_id')::uuid);
+
+-- name: LabelChirp :one
+UPDATE chirps
+SET content_warning = $1, sensitive = $2
+WHERE id = $3
+RETURNING *;
+
+-- name: ListUnparsedChirps :many
+SELECT id, body FROM chirps
+WHERE entities = 'null'::jsonb
+LIMIT 500;
+
+-- name: SetChirpEntities :exec
+UPDATE chirps
+SET entities = $1
+WHERE id = $2;
@@ -0,0 +1,58 @@
+-- name: CreateExportJob :one
+INSERT INTO export_jobs (id, user_id)
+VALUES (gen_random_uuid(), $1)
+RETURNING *;
+
+-- name: GetExportJob :one
+SELECT * FROM export_jobs
+WHERE id = $1;
+
+-- name: TouchExportJob :exec
+UPDATE export_jobs
+SET heartbeat_at = NOW()
+WHERE id = $1 AND status = 'pending';
+
+-- name: CompleteExportJob :one
+UPDATE export_jobs
+SET status = 'done', updated_at = NOW(), completed_at = NOW()
+WHERE id = $1 AND status = 'pending'
+RETURNING *;
+
+-- name: FailExportJob :exec
+UPDATE export_jobs
+SET status = 'failed', updated_at = NOW(), error = $2
+WHERE id = $1 AND status = 'pending';
+
+-- name: FailStaleExportJobs :exec
+UPDATE export_jobs
+SET status = 'failed', updated_at = NOW(), error = 'Export stopped responding'
+WHERE status = 'pending' AND heartbeat_at < $1;
+
+-- name: ListExpiredExportJobs :many
+SELECT id FROM export_jobs
+WHERE status = 'done' AND completed_at < $1;
+
+-- name: ExpireExportJob :exec
+UPDATE export_jobs
+SET status = 'expired', updated_at = NOW()
+WHERE id = $1 AND status = 'done';
+
+-- name: ListChirpsForExport :many
+SELECT * FROM chirps
+WHERE user_id = $1 AND NOT is_tombstone
+ORDER BY created_at, id;
+
+-- name: ListLikesForExport :many
+SELECT * FROM chirp_likes
+WHERE user_id = $1
+ORDER BY created_at, chirp_id;
+
+-- name: ListMediaForExport :many
+SELECT * FROM media
+WHERE user_id = $1
+ORDER BY created_at, id;
+
+-- name: ListSessionsForExport :many
+SELECT created_at, updated_at, expires_at, revoked_at FROM refresh_tokens
+WHERE user_id = $1
+ORDER BY created_at;
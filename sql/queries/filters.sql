@@ -0,0 +1,19 @@
+-- name: ListFilterWords :many
+SELECT * FROM filter_words
+ORDER BY word;
+
+-- name: CreateFilterWord :one
+INSERT INTO filter_words (id, word, action)
+VALUES (gen_random_uuid(), $1, $2)
+RETURNING *;
+
+-- name: UpdateFilterWord :one
+UPDATE filter_words
+SET action = $1, updated_at = NOW()
+WHERE id = $2
+RETURNING *;
+
+-- name: DeleteFilterWord :one
+DELETE FROM filter_words
+WHERE id = $1
+RETURNING *;
@@ -0,0 +1,18 @@
+-- name: AddChirpHashtag :exec
+INSERT INTO chirp_hashtags (chirp_id, tag)
+VALUES ($1, $2)
+ON CONFLICT DO NOTHING;
+
+-- name: ListChirpsForHashtag :many
+SELECT chirps.* FROM chirps
+INNER JOIN chirp_hashtags ON chirps.id = chirp_hashtags.chirp_id
+WHERE chirp_hashtags.tag = sqlc.arg('tag')
+AND (sqlc.narg('after_created_at')::timestamp IS NULL OR (chirps.created_at, chirps.id) < (sqlc.narg('after_created_at')::timestamp, sqlc.narg('after_id')::uuid))
+AND NOT chirps.is_tombstone AND chirps.deleted_at IS NULL
+AND chirp_visible_to(chirps.id, chirps.user_id, chirps.published, chirps.hidden_at, chirps.visibility, sqlc.narg('viewer_id')::uuid)
+ORDER BY chirps.created_at DESC, chirps.id DESC
+LIMIT sqlc.arg('page_size');
+
+-- name: DeleteHashtagsForChirp :exec
+DELETE FROM chirp_hashtags
+WHERE chirp_id = $1;
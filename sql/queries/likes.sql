@@ -0,0 +1,25 @@
+-- name: AddLike :exec
+INSERT INTO chirp_likes (user_id, chirp_id)
+VALUES ($1, $2)
+ON CONFLICT DO NOTHING;
+
+-- name: DeleteLike :exec
+DELETE FROM chirp_likes
+WHERE user_id = $1 AND chirp_id = $2;
+
+-- name: CountLikesForChirps :many
+SELECT chirp_id, COUNT(*) AS like_count FROM chirp_likes
+WHERE chirp_id = ANY(sqlc.arg('chirp_ids')::uuid[])
+GROUP BY chirp_id;
+
+-- name: GetLikedChirpIDs :many
+SELECT chirp_id FROM chirp_likes
+WHERE user_id = sqlc.arg('user_id') AND chirp_id = ANY(sqlc.arg('chirp_ids')::uuid[]);
+
+-- name: ListChirpLikers :many
+SELECT users.id, users.handle, chirp_likes.created_at AS liked_at FROM chirp_likes
+INNER JOIN users ON chirp_likes.user_id = users.id
+WHERE chirp_likes.chirp_id = sqlc.arg('chirp_id')
+AND (sqlc.narg('after_created_at')::timestamp IS NULL OR (chirp_likes.created_at, users.id) < (sqlc.narg('after_created_at')::timestamp, sqlc.narg('after_id')::uuid))
+ORDER BY chirp_likes.created_at DESC, users.id DESC
+LIMIT sqlc.arg('page_size');
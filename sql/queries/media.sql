@@ -0,0 +1,19 @@
+-- name: CreateMedia :one
+INSERT INTO media (id, user_id, sha256, mime_type, size_bytes, width, height, thumbnail_sha256, thumbnail_mime_type, alt_text)
+VALUES (gen_random_uuid(), $1, $2, $3, $4, $5, $6, $7, $8, $9)
+RETURNING *;
+
+-- name: GetMedia :one
+SELECT * FROM media
+WHERE id = $1;
+
+-- name: AttachMedia :one
+UPDATE media
+SET chirp_id = $1, position = $2
+WHERE id = $3 AND user_id = $4 AND chirp_id IS NULL
+RETURNING *;
+
+-- name: GetMediaForChirps :many
+SELECT * FROM media
+WHERE chirp_id = ANY(sqlc.arg('chirp_ids')::uuid[])
+ORDER BY chirp_id, position;
@@ -0,0 +1,23 @@
+-- name: AddChirpMention :exec
+INSERT INTO chirp_mentions (chirp_id, user_id)
+VALUES ($1, $2)
+ON CONFLICT DO NOTHING;
+
+-- name: GetMentionsForChirps :many
+SELECT chirp_mentions.chirp_id, users.id AS user_id, users.handle FROM chirp_mentions
+INNER JOIN users ON chirp_mentions.user_id = users.id
+WHERE chirp_mentions.chirp_id = ANY(sqlc.arg('chirp_ids')::uuid[]);
+
+-- name: ListChirpsMentioningUser :many
+SELECT chirps.* FROM chirps
+INNER JOIN chirp_mentions ON chirps.id = chirp_mentions.chirp_id
+WHERE chirp_mentions.user_id = sqlc.arg('user_id')
+AND (sqlc.narg('after_created_at')::timestamp IS NULL OR (chirps.created_at, chirps.id) < (sqlc.narg('after_created_at')::timestamp, sqlc.narg('after_id')::uuid))
+AND NOT chirps.is_tombstone AND chirps.deleted_at IS NULL
+AND chirp_visible_to(chirps.id, chirps.user_id, chirps.published, chirps.hidden_at, chirps.visibility, sqlc.narg('viewer_id')::uuid)
+ORDER BY chirps.created_at DESC, chirps.id DESC
+LIMIT sqlc.arg('page_size');
+
+-- name: DeleteMentionsForChirp :exec
+DELETE FROM chirp_mentions
+WHERE chirp_id = $1;
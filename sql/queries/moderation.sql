@@ -0,0 +1,61 @@
+-- name: AddReport :one
+INSERT INTO chirp_reports (id, chirp_id, reporter_id, reason, note)
+VALUES (gen_random_uuid(), $1, $2, $3, $4)
+RETURNING *;
+
+-- name: CountOpenReports :one
+SELECT COUNT(*) FROM chirp_reports
+WHERE chirp_id = $1 AND status = 'open';
+
+-- name: ListReportsForChirp :many
+SELECT * FROM chirp_reports
+WHERE chirp_id = $1
+ORDER BY created_at;
+
+-- name: ResolveReports :exec
+UPDATE chirp_reports
+SET status = $1
+WHERE chirp_id = $2 AND status = 'open';
+
+-- name: ListModerationQueue :many
+SELECT chirps.id AS chirp_id, chirps.user_id, chirps.body, chirps.flagged, chirps.hidden_at,
+    COUNT(chirp_reports.id) AS report_count,
+    array_remove(array_agg(DISTINCT chirp_reports.reason), NULL)::text[] AS reasons,
+    COALESCE(MIN(chirp_reports.created_at), chirps.created_at)::timestamp AS queued_at
+FROM chirps
+LEFT JOIN chirp_reports ON chirps.id = chirp_reports.chirp_id AND chirp_reports.status = 'open'
+WHERE NOT chirps.is_tombstone AND chirps.deleted_at IS NULL
+AND (chirps.flagged OR chirp_reports.id IS NOT NULL)
+GROUP BY chirps.id
+ORDER BY report_count DESC, queued_at ASC
+LIMIT $1;
+
+-- name: AddModerationDecision :one
+INSERT INTO moderation_decisions (id, chirp_id, action, note)
+VALUES (gen_random_uuid(), $1, $2, $3)
+RETURNING *;
+
+-- name: ListDecisionsForChirp :many
+SELECT * FROM moderation_decisions
+WHERE chirp_id = $1
+ORDER BY created_at;
+
+-- name: AddAppeal :one
+INSERT INTO chirp_appeals (chirp_id, user_id, note)
+VALUES ($1, $2, $3)
+RETURNING *;
+
+-- name: GetAppeal :one
+SELECT * FROM chirp_appeals
+WHERE chirp_id = $1;
+
+-- name: ListOpenAppeals :many
+SELECT * FROM chirp_appeals
+WHERE status = 'open'
+ORDER BY created_at ASC
+LIMIT $1;
+
+-- name: ResolveAppeal :exec
+UPDATE chirp_appeals
+SET status = $1, resolved_at = NOW()
+WHERE chirp_id = $2 AND status = 'open';
@@ -0,0 +1,19 @@
+-- name: AddPin :exec
+INSERT INTO pinned_chirps (user_id, chirp_id, position)
+VALUES ($1, $2, $3);
+
+-- name: DeletePinsForUser :exec
+DELETE FROM pinned_chirps
+WHERE user_id = $1;
+
+-- name: DeletePinsForChirp :exec
+DELETE FROM pinned_chirps
+WHERE chirp_id = $1;
+
+-- name: ListPinnedChirps :many
+SELECT chirps.* FROM pinned_chirps
+INNER JOIN chirps ON pinned_chirps.chirp_id = chirps.id
+WHERE pinned_chirps.user_id = sqlc.arg('user_id')
+AND NOT chirps.is_tombstone AND chirps.deleted_at IS NULL
+AND chirp_visible_to(chirps.id, chirps.user_id, chirps.published, chirps.hidden_at, chirps.visibility, sqlc.narg('viewer_id')::uuid)
+ORDER BY pinned_chirps.position ASC;
@@ -0,0 +1,27 @@
+-- name: CreatePoll :exec
+INSERT INTO polls (chirp_id, closes_at)
+VALUES ($1, $2);
+
+-- name: AddPollOption :exec
+INSERT INTO poll_options (id, chirp_id, position, label)
+VALUES (gen_random_uuid(), $1, $2, $3);
+
+-- name: GetPoll :one
+SELECT * FROM polls
+WHERE chirp_id = $1;
+
+-- name: AddPollVote :exec
+INSERT INTO poll_votes (chirp_id, user_id, option_id)
+VALUES ($1, $2, $3);
+
+-- name: GetPollOptionsForChirps :many
+SELECT poll_options.id, poll_options.chirp_id, poll_options.label, polls.closes_at, COUNT(poll_votes.user_id) AS vote_count FROM poll_options
+INNER JOIN polls ON poll_options.chirp_id = polls.chirp_id
+LEFT JOIN poll_votes ON poll_options.id = poll_votes.option_id
+WHERE poll_options.chirp_id = ANY(sqlc.arg('chirp_ids')::uuid[])
+GROUP BY poll_options.id, polls.closes_at
+ORDER BY poll_options.chirp_id, poll_options.position;
+
+-- name: GetPollVotesByUser :many
+SELECT chirp_id, option_id FROM poll_votes
+WHERE user_id = sqlc.arg('user_id') AND chirp_id = ANY(sqlc.arg('chirp_ids')::uuid[]);
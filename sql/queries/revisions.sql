@@ -0,0 +1,8 @@
+-- name: AddChirpRevision :exec
+INSERT INTO chirp_revisions (id, chirp_id, body)
+VALUES (gen_random_uuid(), $1, $2);
+
+-- name: ListChirpRevisions :many
+SELECT * FROM chirp_revisions
+WHERE chirp_id = $1
+ORDER BY created_at DESC;
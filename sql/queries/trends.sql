@@ -0,0 +1,12 @@
+-- name: SaveTrendCount :exec
+INSERT INTO trend_counts (bucket, kind, term, count)
+VALUES ($1, $2, $3, $4)
+ON CONFLICT (bucket, kind, term) DO UPDATE SET count = trend_counts.count + EXCLUDED.count, updated_at = NOW();
+
+-- name: ListTrendCounts :many
+SELECT * FROM trend_counts
+WHERE bucket >= $1 AND updated_at > $2;
+
+-- name: DeleteTrendCountsBefore :exec
+DELETE FROM trend_counts
+WHERE bucket < $1;
@@ -1,29 +1,48 @@
 -- name: CreateUser :one
-INSERT INTO users (id, email, hashed_password)
+INSERT INTO users (id, email, hashed_password, handle)
 VALUES (
-    gen_random_uuid(), $1, $2
+    gen_random_uuid(), $1, $2, $3
 )
-RETURNING id, email, created_at, updated_at, is_chirpy_red;
+RETURNING id, email, handle, created_at, updated_at, is_chirpy_red;
 
 -- name: GetUserFromEmail :one
 SELECT * FROM users
 WHERE email = $1;
 
 -- name: GetUser :one
-SELECT id, created_at, updated_at, email, is_chirpy_red FROM users
+SELECT id, created_at, updated_at, email, handle, is_chirpy_red, suspended_at, sensitive_content FROM users
 WHERE id = $1;
 
+-- name: GetUsersByHandles :many
+SELECT id, handle FROM users
+WHERE handle = ANY(sqlc.arg('handles')::text[]);
+
 -- name: DeleteAll :exec
 DELETE FROM users;
 
 -- name: UpdateUser :one
 UPDATE users
-SET email = $1, hashed_password = $2, updated_at = NOW()
-WHERE id = $3
-RETURNING id, email, created_at, updated_at, is_chirpy_red;
+SET email = sqlc.arg('email'), hashed_password = sqlc.arg('hashed_password'), handle = COALESCE(sqlc.narg('handle')::text, handle), updated_at = NOW()
+WHERE id = sqlc.arg('id')
+RETURNING id, email, handle, created_at, updated_at, is_chirpy_red;
 
 -- name: SetChirpyRedForUser :one
 UPDATE users
 SET is_chirpy_red = $1
 WHERE id = $2
-RETURNING id, email, created_at, updated_at, is_chirpy_red;
+RETURNING id, email, handle, created_at, updated_at, is_chirpy_red;
+
+-- name: SuspendUser :exec
+UPDATE users
+SET suspended_at = COALESCE(suspended_at, NOW())
+WHERE id = $1;
+
+-- name: UnsuspendUser :exec
+UPDATE users
+SET suspended_at = NULL
+WHERE id = $1;
+
+-- name: SetSensitiveContent :exec
+UPDATE users
+SET sensitive_content = $1, updated_at = NOW()
+WHERE id = $2;
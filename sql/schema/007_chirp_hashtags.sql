@@ -0,0 +1,11 @@
+-- +goose Up
+CREATE TABLE chirp_hashtags(
+	chirp_id UUID NOT NULL REFERENCES chirps (id) ON DELETE CASCADE,
+	tag TEXT NOT NULL,
+	PRIMARY KEY (chirp_id, tag)
+);
+
+CREATE INDEX chirp_hashtags_tag_idx ON chirp_hashtags (tag);
+
+-- +goose Down
+DROP TABLE chirp_hashtags;
@@ -0,0 +1,15 @@
+-- +goose Up
+ALTER TABLE chirps
+ADD COLUMN rechirp_of UUID REFERENCES chirps (id) ON DELETE CASCADE,
+ADD COLUMN quote_of UUID REFERENCES chirps (id) ON DELETE SET NULL;
+
+CREATE UNIQUE INDEX chirps_rechirp_idx ON chirps (user_id, rechirp_of) WHERE rechirp_of IS NOT NULL;
+CREATE INDEX chirps_rechirp_of_idx ON chirps (rechirp_of);
+
+-- +goose Down
+DROP INDEX chirps_rechirp_of_idx;
+DROP INDEX chirps_rechirp_idx;
+
+ALTER TABLE chirps
+DROP COLUMN rechirp_of,
+DROP COLUMN quote_of;
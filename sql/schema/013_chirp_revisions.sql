@@ -0,0 +1,12 @@
+-- +goose Up
+CREATE TABLE chirp_revisions(
+	id UUID PRIMARY KEY NOT NULL,
+	created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+	chirp_id UUID NOT NULL REFERENCES chirps (id) ON DELETE CASCADE,
+	body TEXT NOT NULL
+);
+
+CREATE INDEX chirp_revisions_chirp_id_idx ON chirp_revisions (chirp_id, created_at);
+
+-- +goose Down
+DROP TABLE chirp_revisions;
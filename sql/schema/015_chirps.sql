@@ -0,0 +1,13 @@
+-- +goose Up
+ALTER TABLE chirps
+ADD COLUMN publish_at TIMESTAMP,
+ADD COLUMN published BOOLEAN NOT NULL DEFAULT true;
+
+CREATE INDEX chirps_scheduled_idx ON chirps (publish_at) WHERE NOT published;
+
+-- +goose Down
+DROP INDEX chirps_scheduled_idx;
+
+ALTER TABLE chirps
+DROP COLUMN publish_at,
+DROP COLUMN published;
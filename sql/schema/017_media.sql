@@ -0,0 +1,21 @@
+-- +goose Up
+CREATE TABLE media(
+	id UUID PRIMARY KEY NOT NULL,
+	created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+	user_id UUID NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+	chirp_id UUID REFERENCES chirps (id) ON DELETE CASCADE,
+	position INTEGER NOT NULL DEFAULT 0,
+	sha256 TEXT NOT NULL,
+	mime_type TEXT NOT NULL,
+	size_bytes BIGINT NOT NULL,
+	width INTEGER NOT NULL,
+	height INTEGER NOT NULL,
+	thumbnail_sha256 TEXT NOT NULL,
+	thumbnail_mime_type TEXT NOT NULL,
+	alt_text TEXT NOT NULL DEFAULT ''
+);
+
+CREATE INDEX media_chirp_id_idx ON media (chirp_id, position);
+
+-- +goose Down
+DROP TABLE media;
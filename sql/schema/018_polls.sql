@@ -0,0 +1,33 @@
+-- +goose Up
+CREATE TABLE polls(
+	chirp_id UUID PRIMARY KEY NOT NULL REFERENCES chirps (id) ON DELETE CASCADE,
+	created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+	closes_at TIMESTAMP NOT NULL
+);
+
+CREATE TABLE poll_options(
+	id UUID PRIMARY KEY NOT NULL,
+	chirp_id UUID NOT NULL REFERENCES polls (chirp_id) ON DELETE CASCADE,
+	position INTEGER NOT NULL,
+	label TEXT NOT NULL,
+	UNIQUE (chirp_id, id),
+	UNIQUE (chirp_id, position)
+);
+
+-- The primary key allows one vote per user per poll, and the composite
+-- foreign key makes sure the option belongs to the poll being voted on.
+CREATE TABLE poll_votes(
+	chirp_id UUID NOT NULL REFERENCES polls (chirp_id) ON DELETE CASCADE,
+	user_id UUID NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+	option_id UUID NOT NULL,
+	created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+	PRIMARY KEY (chirp_id, user_id),
+	FOREIGN KEY (chirp_id, option_id) REFERENCES poll_options (chirp_id, id) ON DELETE CASCADE
+);
+
+CREATE INDEX poll_votes_option_id_idx ON poll_votes (option_id);
+
+-- +goose Down
+DROP TABLE poll_votes;
+DROP TABLE poll_options;
+DROP TABLE polls;
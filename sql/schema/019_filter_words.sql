@@ -0,0 +1,17 @@
+-- +goose Up
+CREATE TABLE filter_words(
+	id UUID PRIMARY KEY NOT NULL,
+	created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+	updated_at TIMESTAMP NOT NULL DEFAULT NOW(),
+	word TEXT NOT NULL UNIQUE,
+	action TEXT NOT NULL CHECK (action IN ('mask', 'reject', 'flag'))
+);
+
+INSERT INTO filter_words (id, word, action)
+VALUES
+	(gen_random_uuid(), 'kerfuffle', 'mask'),
+	(gen_random_uuid(), 'sharbert', 'mask'),
+	(gen_random_uuid(), 'fornax', 'mask');
+
+-- +goose Down
+DROP TABLE filter_words;
@@ -0,0 +1,7 @@
+-- +goose Up
+ALTER TABLE chirps
+ADD COLUMN flagged BOOLEAN NOT NULL DEFAULT false;
+
+-- +goose Down
+ALTER TABLE chirps
+DROP COLUMN flagged;
@@ -0,0 +1,16 @@
+-- +goose Up
+CREATE TABLE chirp_reports(
+	id UUID PRIMARY KEY NOT NULL,
+	created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+	chirp_id UUID NOT NULL REFERENCES chirps (id) ON DELETE CASCADE,
+	reporter_id UUID NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+	reason TEXT NOT NULL,
+	note TEXT NOT NULL DEFAULT '',
+	status TEXT NOT NULL DEFAULT 'open' CHECK (status IN ('open', 'dismissed', 'actioned')),
+	UNIQUE (chirp_id, reporter_id)
+);
+
+CREATE INDEX chirp_reports_open_idx ON chirp_reports (chirp_id) WHERE status = 'open';
+
+-- +goose Down
+DROP TABLE chirp_reports;
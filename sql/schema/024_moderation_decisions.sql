@@ -0,0 +1,13 @@
+-- +goose Up
+CREATE TABLE moderation_decisions(
+	id UUID PRIMARY KEY NOT NULL,
+	created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+	chirp_id UUID NOT NULL REFERENCES chirps (id) ON DELETE CASCADE,
+	action TEXT NOT NULL,
+	note TEXT NOT NULL DEFAULT ''
+);
+
+CREATE INDEX moderation_decisions_chirp_id_idx ON moderation_decisions (chirp_id, created_at);
+
+-- +goose Down
+DROP TABLE moderation_decisions;
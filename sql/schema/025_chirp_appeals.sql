@@ -0,0 +1,13 @@
+-- +goose Up
+-- Keyed by chirp so each hidden chirp can only be appealed once.
+CREATE TABLE chirp_appeals(
+	chirp_id UUID PRIMARY KEY NOT NULL REFERENCES chirps (id) ON DELETE CASCADE,
+	created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+	user_id UUID NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+	note TEXT NOT NULL DEFAULT '',
+	status TEXT NOT NULL DEFAULT 'open' CHECK (status IN ('open', 'accepted', 'rejected')),
+	resolved_at TIMESTAMP
+);
+
+-- +goose Down
+DROP TABLE chirp_appeals;
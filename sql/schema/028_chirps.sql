@@ -0,0 +1,7 @@
+-- +goose Up
+ALTER TABLE chirps
+ADD COLUMN visibility TEXT NOT NULL DEFAULT 'public' CHECK (visibility IN ('public', 'followers', 'mentioned', 'private'));
+
+-- +goose Down
+ALTER TABLE chirps
+DROP COLUMN visibility;
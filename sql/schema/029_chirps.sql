@@ -0,0 +1,9 @@
+-- +goose Up
+ALTER TABLE chirps
+ADD COLUMN content_warning TEXT NOT NULL DEFAULT '',
+ADD COLUMN sensitive BOOLEAN NOT NULL DEFAULT false;
+
+-- +goose Down
+ALTER TABLE chirps
+DROP COLUMN content_warning,
+DROP COLUMN sensitive;
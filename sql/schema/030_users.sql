@@ -0,0 +1,7 @@
+-- +goose Up
+ALTER TABLE users
+ADD COLUMN sensitive_content TEXT NOT NULL DEFAULT 'blur' CHECK (sensitive_content IN ('hide', 'blur', 'show'));
+
+-- +goose Down
+ALTER TABLE users
+DROP COLUMN sensitive_content;
@@ -0,0 +1,9 @@
+-- +goose Up
+-- A JSON null marks chirps written before entities were parsed. The server
+-- fills them in on startup.
+ALTER TABLE chirps
+ADD COLUMN entities JSONB NOT NULL DEFAULT 'null';
+
+-- +goose Down
+ALTER TABLE chirps
+DROP COLUMN entities;
@@ -0,0 +1,13 @@
+-- +goose Up
+-- How often each term was used per bucket, summed over every instance, so
+-- instances share trends and a restart doesn't reset them.
+CREATE TABLE trend_counts(
+	bucket TIMESTAMP NOT NULL,
+	kind TEXT NOT NULL,
+	term TEXT NOT NULL,
+	count INTEGER NOT NULL,
+	PRIMARY KEY (bucket, kind, term)
+);
+
+-- +goose Down
+DROP TABLE trend_counts;
@@ -0,0 +1,19 @@
+-- +goose Up
+CREATE TABLE export_jobs(
+	id UUID PRIMARY KEY NOT NULL,
+	created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+	updated_at TIMESTAMP NOT NULL DEFAULT NOW(),
+	user_id UUID NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+	status TEXT NOT NULL DEFAULT 'pending' CHECK (status IN ('pending', 'done', 'failed', 'expired')),
+	error TEXT NOT NULL DEFAULT '',
+	completed_at TIMESTAMP,
+	-- Running exports touch heartbeat_at so any instance can tell when the one
+	-- building an archive has died.
+	heartbeat_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+-- A user only gets one export running at a time.
+CREATE UNIQUE INDEX export_jobs_pending_idx ON export_jobs (user_id) WHERE status = 'pending';
+
+-- +goose Down
+DROP TABLE export_jobs;
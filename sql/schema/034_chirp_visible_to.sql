@@ -0,0 +1,20 @@
+-- +goose Up
+-- chirp_visible_to is the one place that decides who can see a chirp, so
+-- every listing applies the same rule. Authors always see their own chirps.
+-- Everyone else needs them published and not hidden by moderation, and then
+-- followers and mentioned users only see what's been shared with them.
+-- viewer_id is null for anonymous callers.
+-- +goose StatementBegin
+CREATE FUNCTION chirp_visible_to(chirp_id UUID, author_id UUID, published BOOLEAN, hidden_at TIMESTAMP, visibility TEXT, viewer_id UUID)
+RETURNS BOOLEAN
+LANGUAGE SQL STABLE
+AS $$
+    SELECT (author_id = viewer_id) IS TRUE
+        OR (published AND hidden_at IS NULL AND (visibility = 'public'
+            OR (visibility IN ('followers', 'mentioned') AND EXISTS (SELECT 1 FROM chirp_mentions WHERE chirp_mentions.chirp_id = chirp_visible_to.chirp_id AND chirp_mentions.user_id = chirp_visible_to.viewer_id))
+            OR (visibility = 'followers' AND EXISTS (SELECT 1 FROM follows WHERE follows.follower_id = chirp_visible_to.viewer_id AND follows.followee_id = chirp_visible_to.author_id))))
+$$;
+-- +goose StatementEnd
+
+-- +goose Down
+DROP FUNCTION chirp_visible_to(UUID, UUID, BOOLEAN, TIMESTAMP, TEXT, UUID);
@@ -0,0 +1,10 @@
+-- +goose Up
+-- updated_at lets each instance load only the counts that changed since it
+-- last looked, rather than every count it keeps.
+ALTER TABLE trend_counts
+ADD COLUMN updated_at TIMESTAMP NOT NULL DEFAULT NOW();
+CREATE INDEX trend_counts_updated_at_idx ON trend_counts (updated_at);
+
+-- +goose Down
+ALTER TABLE trend_counts
+DROP COLUMN updated_at;
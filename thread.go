@@ -0,0 +1,147 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"log"
+	"net/http"
+
+	"github.com/cameronbarnes/go_chirpy/internal/database"
+	"github.com/google/uuid"
+)
+
+type threadNode struct {
+	chirpOut
+	Replies []*threadNode `json:"replies"`
+}
+
+func (c *apiConfig) getThread(w http.ResponseWriter, r *http.Request, viewer *database.GetUserRow) {
+	id, err := uuid.Parse(r.PathValue("chirpID"))
+	if err != nil {
+		respondWithError(w, 400, "UUID provided is not valid")
+		return
+	}
+	chirp, err := c.db.GetChirp(context.Background(), id)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			respondWithError(w, 404, "Chirp Not Found")
+		} else {
+			log.Println(err)
+			respondWithError(w, 500, "Failed to get Chirp")
+		}
+		return
+	}
+	ancestors, err := c.db.GetChirpAncestors(context.Background(), id)
+	if err != nil {
+		log.Printf("Failed to get ancestors with err: %s", err)
+		respondWithError(w, 500, "Failed to get thread")
+		return
+	}
+	descendants, err := c.db.GetChirpDescendants(context.Background(), id)
+	if err != nil {
+		log.Printf("Failed to get replies with err: %s", err)
+		respondWithError(w, 500, "Failed to get thread")
+		return
+	}
+	visible, err := c.visibleChirps(append(append([]database.Chirp{chirp}, ancestors...), descendants...), viewer)
+	if err != nil {
+		log.Printf("Failed to check chirp visibility with err: %s", err)
+		respondWithError(w, 500, "Failed to get thread")
+		return
+	}
+	if !visible[chirp.ID] {
+		respondWithError(w, 404, "Chirp Not Found")
+		return
+	}
+	ancestorsOut, err := c.buildThreadChirps(ancestors, visible, viewer)
+	if err != nil {
+		log.Printf("Failed to build thread with err: %s", err)
+		respondWithError(w, 500, "Failed to get thread")
+		return
+	}
+	built, err := c.buildThreadChirps(append([]database.Chirp{chirp}, descendants...), visible, viewer)
+	if err != nil {
+		log.Printf("Failed to build thread with err: %s", err)
+		respondWithError(w, 500, "Failed to get thread")
+		return
+	}
+	root := buildThreadTree(built)
+	// A live chirp only comes back as a placeholder when it's a rechirp of
+	// something that's gone, so there's no thread to show.
+	if root == nil || (root.IsTombstone && !chirpRemoved(chirp)) {
+		respondWithError(w, 404, "Chirp Not Found")
+		return
+	}
+	type thread struct {
+		Ancestors []chirpOut  `json:"ancestors"`
+		Chirp     *threadNode `json:"chirp"`
+	}
+	respondWithJSON(w, 200, thread{Ancestors: ancestorsOut, Chirp: root})
+}
+
+// buildThreadChirps builds the chirps in a thread, in the order given.
+// Deleted chirps, and ones the viewer can't see, become placeholders so the
+// thread keeps its shape without showing anything about them.
+func (c *apiConfig) buildThreadChirps(chirps []database.Chirp, visible map[uuid.UUID]bool, viewer *database.GetUserRow) ([]chirpOut, error) {
+	shown := []database.Chirp{}
+	for _, chirp := range chirps {
+		if visible[chirp.ID] && !chirpRemoved(chirp) {
+			shown = append(shown, chirp)
+		}
+	}
+	built, err := c.buildChirps(shown, viewer)
+	if err != nil {
+		return nil, err
+	}
+	byID := make(map[uuid.UUID]chirpOut, len(built))
+	for _, item := range built {
+		byID[item.ID] = item
+	}
+	out := make([]chirpOut, len(chirps))
+	for i, chirp := range chirps {
+		item, ok := byID[chirp.ID]
+		if !ok {
+			item = threadPlaceholder(chirp)
+		}
+		out[i] = item
+	}
+	return out, nil
+}
+
+// threadPlaceholder stands in for a chirp the viewer doesn't get to see. It
+// keeps only what's needed to place it in the thread.
+func threadPlaceholder(chirp database.Chirp) chirpOut {
+	return chirpOut{
+		Chirp: database.Chirp{
+			ID:          chirp.ID,
+			ParentID:    chirp.ParentID,
+			IsTombstone: true,
+			Entities:    json.RawMessage("[]"),
+		},
+		Mentions: []mentionOut{},
+		Media:    []mediaOut{},
+		Display:  displayFull,
+	}
+}
+
+// buildThreadTree nests replies under their parents. The first chirp is the
+// root, and the rest must be its descendants ordered oldest first. It returns
+// nil if there are no chirps.
+func buildThreadTree(chirps []chirpOut) *threadNode {
+	if len(chirps) == 0 {
+		return nil
+	}
+	nodes := make(map[uuid.UUID]*threadNode, len(chirps))
+	for _, chirp := range chirps {
+		nodes[chirp.ID] = &threadNode{chirpOut: chirp, Replies: []*threadNode{}}
+	}
+	root := nodes[chirps[0].ID]
+	for _, chirp := range chirps[1:] {
+		if parent, ok := nodes[chirp.ParentID.UUID]; ok {
+			parent.Replies = append(parent.Replies, nodes[chirp.ID])
+		}
+	}
+	return root
+}
@@ -0,0 +1,38 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/cameronbarnes/go_chirpy/internal/database"
+	"github.com/google/uuid"
+)
+
+func threadChirp(id, parent uuid.UUID) chirpOut {
+	return chirpOut{Chirp: database.Chirp{ID: id, ParentID: uuid.NullUUID{UUID: parent, Valid: parent != uuid.Nil}}}
+}
+
+func TestBuildThreadTreeKeepsRepliesUnderPlaceholders(t *testing.T) {
+	root, hidden, reply := uuid.New(), uuid.New(), uuid.New()
+	chirps := []chirpOut{
+		threadChirp(root, uuid.Nil),
+		threadPlaceholder(database.Chirp{ID: hidden, ParentID: uuid.NullUUID{UUID: root, Valid: true}, Body: "secret"}),
+		threadChirp(reply, hidden),
+	}
+	tree := buildThreadTree(chirps)
+	if tree == nil || tree.ID != root || len(tree.Replies) != 1 {
+		t.Fatalf("Unexpected tree: %+v", tree)
+	}
+	placeholder := tree.Replies[0]
+	if !placeholder.IsTombstone || placeholder.Body != "" {
+		t.Errorf("Hidden reply should be a bare placeholder, got: %+v", placeholder.chirpOut)
+	}
+	if len(placeholder.Replies) != 1 || placeholder.Replies[0].ID != reply {
+		t.Errorf("Visible reply should stay under the placeholder, got: %+v", placeholder.Replies)
+	}
+}
+
+func TestBuildThreadTreeEmpty(t *testing.T) {
+	if tree := buildThreadTree(nil); tree != nil {
+		t.Errorf("Expected no tree, got: %+v", tree)
+	}
+}
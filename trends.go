@@ -0,0 +1,162 @@
+package main
+
+import (
+	"context"
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/cameronbarnes/go_chirpy/internal/chirptext"
+	"github.com/cameronbarnes/go_chirpy/internal/database"
+	"github.com/cameronbarnes/go_chirpy/internal/trends"
+)
+
+const (
+	trendBucket   = 5 * time.Minute
+	trendMinCount = 3
+	trendLimit    = 10
+	// trendRetain covers the longest window along with its baseline.
+	trendRetain = 8 * 24 * time.Hour
+	// trendLoadOverlap is how far before the newest count already loaded
+	// each load starts, since a flush's counts are stamped when it starts
+	// but only show up once it commits.
+	trendLoadOverlap = time.Minute
+)
+
+// trendWindows are the windows trends are reported for. Each is compared with
+// the baseline period just before it.
+var trendWindows = []struct {
+	Name     string
+	Window   time.Duration
+	Baseline time.Duration
+}{
+	{Name: "1h", Window: time.Hour, Baseline: 24 * time.Hour},
+	{Name: "24h", Window: 24 * time.Hour, Baseline: 7 * 24 * time.Hour},
+}
+
+// recordTrends counts a chirp's hashtags and phrases once it's public.
+// Flagged chirps are left out so spam waiting on a moderator can't trend.
+func (c *apiConfig) recordTrends(chirp database.Chirp) {
+	if !chirpPublic(chirp) || chirp.Flagged || chirp.RechirpOf.Valid {
+		return
+	}
+	terms := []trends.Term{}
+	for _, tag := range chirptext.Hashtags(chirp.Body) {
+		terms = append(terms, trends.Term{Kind: trends.KindHashtag, Text: tag})
+	}
+	for _, phrase := range chirptext.Phrases(chirp.Body) {
+		terms = append(terms, trends.Term{Kind: trends.KindPhrase, Text: phrase})
+	}
+	c.trends.Add(time.Now(), terms)
+}
+
+// loadTrends brings in the totals saved by every instance. The first load
+// reads them all, and later ones only the counts changed since, going back
+// trendLoadOverlap further for flushes that committed late.
+func (c *apiConfig) loadTrends() error {
+	since := c.trendsLoadedAt
+	if !since.IsZero() {
+		since = since.Add(-trendLoadOverlap)
+	}
+	saved, err := c.db.ListTrendCounts(context.Background(), time.Now().UTC().Add(-trendRetain), since)
+	if err != nil {
+		return err
+	}
+	counts := make([]trends.Count, len(saved))
+	latest := c.trendsLoadedAt
+	for i, count := range saved {
+		counts[i] = trends.Count{Bucket: count.Bucket, Term: trends.Term{Kind: trends.Kind(count.Kind), Text: count.Term}, Count: int(count.Count)}
+		if count.UpdatedAt.After(latest) {
+			latest = count.UpdatedAt
+		}
+	}
+	if c.trendsLoadedAt.IsZero() {
+		c.trends.Load(counts)
+	} else {
+		c.trends.Merge(counts)
+	}
+	c.trendsLoadedAt = latest
+	return nil
+}
+
+// rankTrends works out the trends for every window and keeps them for
+// getTrends, so requests don't each rank every term.
+func (c *apiConfig) rankTrends() {
+	convert := func(top []trends.Trend) []trendOut {
+		out := make([]trendOut, len(top))
+		for i, trend := range top {
+			out[i] = trendOut{Term: trend.Term.Text, Count: trend.Count, Expected: trend.Expected, Score: trend.Score}
+		}
+		return out
+	}
+	now := time.Now()
+	out := trendsOut{}
+	for _, window := range trendWindows {
+		out[window.Name] = trendWindowOut{
+			Hashtags: convert(c.trends.Top(now, trends.KindHashtag, window.Window, window.Baseline, trendMinCount, trendLimit)),
+			Phrases:  convert(c.trends.Top(now, trends.KindPhrase, window.Window, window.Baseline, trendMinCount, trendLimit)),
+		}
+	}
+	c.trendsTop.Store(&out)
+}
+
+// saveTrends runs until the process exits. It adds the uses this instance
+// has seen to the totals in the database, loads back what other instances
+// have added, then ranks the result.
+func (c *apiConfig) saveTrends(interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		<-ticker.C
+		if err := c.flushTrends(); err != nil {
+			log.Printf("Failed to save trends with err: %s", err)
+		}
+		c.rankTrends()
+	}
+}
+
+func (c *apiConfig) flushTrends() error {
+	err := c.trends.Flush(func(counts []trends.Count) error {
+		return c.withTx(func(q *database.Queries) error {
+			for _, count := range counts {
+				err := q.SaveTrendCount(context.Background(), database.SaveTrendCountParams{
+					Bucket: count.Bucket,
+					Kind:   string(count.Term.Kind),
+					Term:   count.Term.Text,
+					Count:  int32(count.Count),
+				})
+				if err != nil {
+					return err
+				}
+			}
+			return nil
+		})
+	})
+	if err != nil {
+		return err
+	}
+	cutoff := c.trends.Prune(time.Now())
+	if err := c.db.DeleteTrendCountsBefore(context.Background(), cutoff); err != nil {
+		return err
+	}
+	return c.loadTrends()
+}
+
+type trendOut struct {
+	Term     string  `json:"term"`
+	Count    int     `json:"count"`
+	Expected float64 `json:"expected"`
+	Score    float64 `json:"score"`
+}
+
+type trendWindowOut struct {
+	Hashtags []trendOut `json:"hashtags"`
+	Phrases  []trendOut `json:"phrases"`
+}
+
+// trendsOut holds the trends for each window, keyed by the window's name.
+type trendsOut map[string]trendWindowOut
+
+func (c *apiConfig) getTrends(w http.ResponseWriter, _ *http.Request) {
+	respondWithJSON(w, 200, *c.trendsTop.Load())
+}
@@ -0,0 +1,36 @@
+package main
+
+import (
+	"encoding/json"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/cameronbarnes/go_chirpy/internal/trends"
+)
+
+func TestGetTrendsServesLastRanking(t *testing.T) {
+	c := &apiConfig{trends: trends.NewAggregator(trendBucket, trendRetain)}
+	c.rankTrends()
+	term := trends.Term{Kind: trends.KindHashtag, Text: "go"}
+	for range trendMinCount {
+		c.trends.Add(time.Now(), []trends.Term{term})
+	}
+
+	get := func() trendsOut {
+		w := httptest.NewRecorder()
+		c.getTrends(w, httptest.NewRequest("GET", "/api/trends", nil))
+		var out trendsOut
+		if err := json.Unmarshal(w.Body.Bytes(), &out); err != nil {
+			t.Fatal(err)
+		}
+		return out
+	}
+	if got := get()["1h"].Hashtags; len(got) != 0 {
+		t.Errorf("Uses since the last ranking shouldn't show yet, got: %+v", got)
+	}
+	c.rankTrends()
+	if got := get()["1h"].Hashtags; len(got) != 1 || got[0].Term != "go" || got[0].Count != trendMinCount {
+		t.Errorf("Expected the new ranking, got: %+v", got)
+	}
+}